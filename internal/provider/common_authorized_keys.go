@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	// principalRegexp matches a single certificate principal. Principals are written as comma separated
+	// lists in authorized_keys options and AuthorizedPrincipalsFile, so commas, quotes and whitespace
+	// can not be used.
+	principalRegexp = regexp.MustCompile(`^[^\s,"]+$`)
+
+	// fromPatternRegexp matches a single host name or address pattern of a `from=` option.
+	fromPatternRegexp = regexp.MustCompile(`^!?[^\s,"!]+$`)
+
+	// optionValueRegexp matches values that can be quoted in an authorized_keys option. sshd only
+	// unescapes `\"`, so a trailing backslash would escape the closing quote.
+	optionValueRegexp = regexp.MustCompile(`^([^\r\n]*[^\r\n\\])?$`)
+)
+
+// principalValidator validates a certificate principal.
+func principalValidator() validator.String {
+	return stringvalidator.RegexMatches(principalRegexp, "must not contain whitespace, commas or double quotes")
+}
+
+// fromPatternValidator validates a host name or address pattern of a `from=` option.
+func fromPatternValidator() validator.String {
+	return stringvalidator.RegexMatches(fromPatternRegexp, "must be a host name or address pattern, optionally negated with `!`")
+}
+
+// optionValueValidator validates the value of an authorized_keys option.
+func optionValueValidator() validator.String {
+	return stringvalidator.RegexMatches(optionValueRegexp, "must not contain line breaks or end with a backslash")
+}
+
+// quoteOption formats an authorized_keys option with a double quoted value. Only double quotes are
+// escaped, as sshd keeps other backslashes as they are.
+func quoteOption(name, value string) string {
+	return name + `="` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+// authorizedKeysOptions holds the options of an authorized_keys or AuthorizedPrincipalsFile line.
+type authorizedKeysOptions struct {
+	certAuthority bool
+	principals    []string
+	from          []string
+	command       string
+}
+
+// String formats the options as a comma separated list, as written at the start of the line.
+func (o authorizedKeysOptions) String() string {
+	var options []string
+	if o.certAuthority {
+		options = append(options, "cert-authority")
+	}
+	if len(o.principals) > 0 {
+		options = append(options, quoteOption("principals", strings.Join(o.principals, ",")))
+	}
+	if len(o.from) > 0 {
+		options = append(options, quoteOption("from", strings.Join(o.from, ",")))
+	}
+	if o.command != "" {
+		options = append(options, quoteOption("command", o.command))
+	}
+	return strings.Join(options, ",")
+}
+
+// authorizedKeysLine formats an authorized_keys line for the key, prefixed by options.
+func authorizedKeysLine(options authorizedKeysOptions, pubKey ssh.PublicKey, comment string) string {
+	line := strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(pubKey)), "\n")
+	if comment != "" {
+		line += " " + comment
+	}
+	if o := options.String(); o != "" {
+		line = o + " " + line
+	}
+	return line
+}
@@ -6,102 +6,584 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
 )
 
-func modifyPlanIfCertificateReadyForRenewal(ctx context.Context, req *resource.ModifyPlanRequest, res *resource.ModifyPlanResponse) {
-	// Retrieve `validity_end_time` and confirm is a known, non-null value
-	validityEndTimePath := path.Root("validity_end_time")
-	var validityEndTimeStr types.String
-	res.Diagnostics.Append(req.Plan.GetAttribute(ctx, validityEndTimePath, &validityEndTimeStr)...)
-	if res.Diagnostics.HasError() {
+func modifyStateIfCertificateReadyForRenewal(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, serialPath path.Path) {
+	// Determine the time from which an "early renewal" is possible
+	earlyRenewalTime, known, diags := getCertificateRenewalTime(ctx, req.State, serialPath)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || !known {
 		return
 	}
+
+	currentTime := overridableTimeFunc()
+
+	// If "early renewal" time has passed, mark it "ready for renewal"
+	timeToEarlyRenewal := earlyRenewalTime.Sub(currentTime)
+	if timeToEarlyRenewal <= 0 {
+		tflog.Info(ctx, "Certificate is ready for early renewal")
+		readyForRenewalPath := path.Root("ready_for_renewal")
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, readyForRenewalPath, true)...)
+	}
+}
+
+// attributeGetter is implemented by tfsdk.Config, tfsdk.Plan and tfsdk.State.
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, path path.Path, target interface{}) diag.Diagnostics
+}
+
+// certificateSchedule holds the attributes that determine the validity period of issued
+// certificates, and when they are ready for renewal.
+type certificateSchedule struct {
+	validityPeriodHours  int64
+	alignExpiryTo        string
+	earlyRenewalHours    types.Int64
+	earlyRenewalFraction types.Float64
+	earlyRenewal         types.String
+	earlyRenewalJitter   time.Duration
+}
+
+// expiryAlignments maps the values of `align_expiry_to` to the boundaries they round up to.
+var expiryAlignments = map[string]time.Duration{
+	"hour": time.Hour,
+	"day":  24 * time.Hour,
+}
+
+// getCertificateSchedule retrieves `validity_period_hours`, `align_expiry_to`, `early_renewal_hours`,
+// `early_renewal_fraction`, `early_renewal` and `early_renewal_jitter`.
+// The schedule is not known if any of the attributes is unknown.
+func getCertificateSchedule(ctx context.Context, data attributeGetter) (certificateSchedule, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var schedule certificateSchedule
+	var validityPeriodHours types.Int64
+	var alignExpiryTo, earlyRenewalJitter types.String
+	diags.Append(data.GetAttribute(ctx, path.Root("validity_period_hours"), &validityPeriodHours)...)
+	diags.Append(data.GetAttribute(ctx, path.Root("align_expiry_to"), &alignExpiryTo)...)
+	diags.Append(data.GetAttribute(ctx, path.Root("early_renewal_hours"), &schedule.earlyRenewalHours)...)
+	diags.Append(data.GetAttribute(ctx, path.Root("early_renewal_fraction"), &schedule.earlyRenewalFraction)...)
+	diags.Append(data.GetAttribute(ctx, path.Root("early_renewal"), &schedule.earlyRenewal)...)
+	diags.Append(data.GetAttribute(ctx, path.Root("early_renewal_jitter"), &earlyRenewalJitter)...)
+	if diags.HasError() || validityPeriodHours.IsUnknown() || alignExpiryTo.IsUnknown() ||
+		schedule.earlyRenewalHours.IsUnknown() || schedule.earlyRenewalFraction.IsUnknown() ||
+		schedule.earlyRenewal.IsUnknown() || earlyRenewalJitter.IsUnknown() {
+		return schedule, false, diags
+	}
+
+	schedule.validityPeriodHours = validityPeriodHours.ValueInt64()
+	schedule.alignExpiryTo = alignExpiryTo.ValueString()
+	if !earlyRenewalJitter.IsNull() {
+		jitter, err := time.ParseDuration(earlyRenewalJitter.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("early_renewal_jitter"), "Failed to parse early renewal jitter", err.Error())
+			return schedule, false, diags
+		}
+		schedule.earlyRenewalJitter = jitter
+	}
+	return schedule, true, diags
+}
+
+// validityEnd returns the end of the validity period of a certificate valid from the given time.
+// With `align_expiry_to`, it is rounded up to the next full hour or day in UTC.
+func (s certificateSchedule) validityEnd(validAfter time.Time) time.Time {
+	validBefore := validAfter.Add(time.Duration(s.validityPeriodHours) * time.Hour)
+	if boundary, ok := expiryAlignments[s.alignExpiryTo]; ok {
+		if aligned := validBefore.Truncate(boundary); aligned.Before(validBefore) {
+			validBefore = aligned.Add(boundary)
+		}
+	}
+	return validBefore
+}
+
+// earlyRenewalPeriod returns how long before the end of a certificate with the given lifetime
+// it is ready for renewal. `early_renewal_fraction` and `early_renewal` take precedence over
+// `early_renewal_hours`.
+func (s certificateSchedule) earlyRenewalPeriod(lifetime time.Duration) (time.Duration, error) {
+	switch {
+	case !s.earlyRenewalFraction.IsNull():
+		return time.Duration(s.earlyRenewalFraction.ValueFloat64() * float64(lifetime)), nil
+	case !s.earlyRenewal.IsNull():
+		return time.ParseDuration(s.earlyRenewal.ValueString())
+	default:
+		return time.Duration(s.earlyRenewalHours.ValueInt64()) * time.Hour, nil
+	}
+}
+
+// renewalTime returns the time from which a certificate is ready for renewal: its early renewal
+// period before its end, brought forward by its share of `early_renewal_jitter`.
+func (s certificateSchedule) renewalTime(validAfter, validBefore time.Time, keyID string, serial uint64) (time.Time, error) {
+	earlyRenewalPeriod, err := s.earlyRenewalPeriod(validBefore.Sub(validAfter))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return validBefore.Add(-earlyRenewalPeriod - renewalJitter(s.earlyRenewalJitter, keyID, serial)), nil
+}
+
+// certificateRenewalTime returns the time from which an issued certificate is ready for renewal.
+func (s certificateSchedule) certificateRenewalTime(certificate *ssh.Certificate) (time.Time, error) {
+	return s.renewalTime(time.Unix(int64(certificate.ValidAfter), 0), time.Unix(int64(certificate.ValidBefore), 0), certificate.KeyId, certificate.Serial)
+}
+
+// renewalJitter returns a duration of up to maxJitter, derived from the key ID and serial number
+// of a certificate. Certificates issued together are thereby renewed at different, but stable, times.
+func renewalJitter(maxJitter time.Duration, keyID string, serial uint64) time.Duration {
+	seconds := uint64(maxJitter / time.Second)
+	if seconds == 0 {
+		return 0
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", keyID, serial)))
+	return time.Duration(binary.BigEndian.Uint64(hash[:8])%(seconds+1)) * time.Second
+}
+
+// getCertificateRenewalTime retrieves the validity period, key ID and serial number of the
+// certificate in the plan or state, and returns the time from which it is ready for renewal.
+// The time is not known if the certificate or its schedule is not known.
+func getCertificateRenewalTime(ctx context.Context, data attributeGetter, serialPath path.Path) (time.Time, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var validityStartTimeStr, validityEndTimeStr, keyID, serialStr types.String
+	diags.Append(data.GetAttribute(ctx, path.Root("validity_start_time"), &validityStartTimeStr)...)
+	diags.Append(data.GetAttribute(ctx, path.Root("validity_end_time"), &validityEndTimeStr)...)
+	diags.Append(data.GetAttribute(ctx, path.Root("key_id"), &keyID)...)
+	diags.Append(data.GetAttribute(ctx, serialPath, &serialStr)...)
+	if diags.HasError() {
+		return time.Time{}, false, diags
+	}
 	if validityEndTimeStr.IsNull() || validityEndTimeStr.IsUnknown() {
-		return
+		return time.Time{}, false, diags
 	}
 
 	// Parse `validity_end_time`
 	validityEndTime, err := time.Parse(time.RFC3339, validityEndTimeStr.ValueString())
 	if err != nil {
-		res.Diagnostics.AddError(
+		diags.AddError(
 			fmt.Sprintf("Failed to parse data from string: %s", validityEndTimeStr.ValueString()),
 			err.Error(),
 		)
-		return
+		return time.Time{}, false, diags
+	}
+	// Certificates without a known start or serial number have no lifetime or jitter
+	validityStartTime := validityEndTime
+	if t, err := time.Parse(time.RFC3339, validityStartTimeStr.ValueString()); err == nil {
+		validityStartTime = t
 	}
+	serial, _ := parseSerial(serialStr.ValueString())
 
-	// Retrieve `early_renewal_hours`
-	earlyRenewalHoursPath := path.Root("early_renewal_hours")
-	var earlyRenewalHours int64
-	res.Diagnostics.Append(req.Plan.GetAttribute(ctx, earlyRenewalHoursPath, &earlyRenewalHours)...)
-	if res.Diagnostics.HasError() {
-		return
+	schedule, known, d := getCertificateSchedule(ctx, data)
+	diags.Append(d...)
+	if diags.HasError() || !known {
+		return time.Time{}, false, diags
 	}
+	renewalTime, err := schedule.renewalTime(validityStartTime, validityEndTime, keyID.ValueString(), serial)
+	if err != nil {
+		diags.AddAttributeError(path.Root("early_renewal"), "Failed to parse early renewal period", err.Error())
+		return time.Time{}, false, diags
+	}
+	return renewalTime, true, diags
+}
 
-	currentTime := overridableTimeFunc()
+// validateCertificateSchedule checks that the planned early renewal period and jitter are shorter
+// than `validity_period_hours`, as a certificate would otherwise be renewed on every apply.
+func validateCertificateSchedule(ctx context.Context, plan tfsdk.Plan) diag.Diagnostics {
+	schedule, known, diags := getCertificateSchedule(ctx, plan)
+	if diags.HasError() || !known {
+		return diags
+	}
+	lifetime := time.Duration(schedule.validityPeriodHours) * time.Hour
+	earlyRenewalPeriod, err := schedule.earlyRenewalPeriod(lifetime)
+	if err != nil {
+		diags.AddAttributeError(path.Root("early_renewal"), "Failed to parse early renewal period", err.Error())
+		return diags
+	}
+	if earlyRenewalPeriod+schedule.earlyRenewalJitter > 0 && earlyRenewalPeriod+schedule.earlyRenewalJitter >= lifetime {
+		diags.AddError(
+			"Invalid early renewal period",
+			fmt.Sprintf("The early renewal period of %s and jitter of %s must together be shorter than the validity period of %s.",
+				earlyRenewalPeriod, schedule.earlyRenewalJitter, lifetime),
+		)
+	}
+	return diags
+}
 
-	// Determine the time from which an "early renewal" is possible
-	earlyRenewalPeriod := time.Duration(-earlyRenewalHours) * time.Hour
-	earlyRenewalTime := validityEndTime.Add(earlyRenewalPeriod)
+// earlyRenewalRegexp matches a duration in hours and minutes, e.g. `36h` or `1h30m`.
+var earlyRenewalRegexp = regexp.MustCompile(`^([0-9]+h)?([0-9]+m)?$`)
 
-	// If "early renewal" time has passed, mark it "ready for renewal"
-	timeToEarlyRenewal := earlyRenewalTime.Sub(currentTime)
-	if timeToEarlyRenewal <= 0 {
-		tflog.Info(ctx, "Certificate is ready for early renewal")
-		readyForRenewalPath := path.Root("ready_for_renewal")
-		res.Diagnostics.Append(res.Plan.SetAttribute(ctx, readyForRenewalPath, types.BoolUnknown())...)
-		res.RequiresReplace = append(res.RequiresReplace, readyForRenewalPath)
+// earlyRenewalFractionAttribute returns the schema of the `early_renewal_fraction` attribute.
+func earlyRenewalFractionAttribute() schema.Float64Attribute {
+	return schema.Float64Attribute{
+		Optional: true,
+		Validators: []validator.Float64{
+			float64validator.Between(0, 1),
+			float64validator.ConflictsWith(path.MatchRoot("early_renewal_hours"), path.MatchRoot("early_renewal")),
+		},
+		Description: "Alternative to `early_renewal_hours`, as a fraction of the validity period of the certificate, " +
+			"e.g. `0.33` to renew it when two thirds of its lifetime have passed. " +
+			"It scales with `validity_period_hours`, so that short and long lived certificates can share a setting.",
+	}
+}
+
+// earlyRenewalAttribute returns the schema of the `early_renewal` attribute.
+func earlyRenewalAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional: true,
+		Validators: []validator.String{
+			stringvalidator.LengthAtLeast(2),
+			stringvalidator.RegexMatches(earlyRenewalRegexp, "must be a duration in hours and minutes, e.g. `36h` or `1h30m`"),
+			stringvalidator.ConflictsWith(path.MatchRoot("early_renewal_hours"), path.MatchRoot("early_renewal_fraction")),
+		},
+		Description: "Alternative to `early_renewal_hours`, as a duration in hours and minutes, e.g. `36h` or `1h30m`.",
+	}
+}
+
+// earlyRenewalJitterAttribute returns the schema of the `early_renewal_jitter` attribute.
+func earlyRenewalJitterAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional: true,
+		Validators: []validator.String{
+			stringvalidator.LengthAtLeast(2),
+			stringvalidator.RegexMatches(earlyRenewalRegexp, "must be a duration in hours and minutes, e.g. `36h` or `1h30m`"),
+		},
+		Description: "Maximum duration, in hours and minutes, by which the early renewal is brought forward. " +
+			"The offset of each certificate is derived from its key ID and serial number. It stays the same " +
+			"across applies, while certificates issued together become ready for renewal at different times.",
+	}
+}
+
+// alignExpiryToAttribute returns the schema of the `align_expiry_to` attribute.
+func alignExpiryToAttribute(planModifiers ...planmodifier.String) schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:      true,
+		PlanModifiers: planModifiers,
+		Validators: []validator.String{
+			stringvalidator.OneOf("hour", "day"),
+		},
+		Description: "Extend the validity period of certificates to the next full `hour` or `day` in UTC, " +
+			"so that they expire on predictable boundaries.",
+	}
+}
+
+// serialRegexp matches a non-zero certificate serial number in decimal.
+var serialRegexp = regexp.MustCompile(`^[1-9][0-9]*$`)
+
+// serialValidator validates a certificate serial number.
+func serialValidator() validator.String {
+	return stringvalidator.RegexMatches(serialRegexp, "must be a non-zero serial number in decimal")
+}
+
+// parseSerial parses a certificate serial number in decimal.
+func parseSerial(s string) (uint64, error) {
+	if !serialRegexp.MatchString(s) {
+		return 0, fmt.Errorf("invalid serial number: %q", s)
 	}
+	return strconv.ParseUint(s, 10, 64)
 }
 
-func modifyStateIfCertificateReadyForRenewal(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	// Retrieve `validity_end_time` and confirm is a known, non-null value
-	validityEndTimePath := path.Root("validity_end_time")
-	var validityEndTimeStr types.String
-	resp.Diagnostics.Append(req.State.GetAttribute(ctx, validityEndTimePath, &validityEndTimeStr)...)
-	if resp.Diagnostics.HasError() {
+func modifyPlanForCertificateComment(ctx context.Context, req *resource.ModifyPlanRequest, res *resource.ModifyPlanResponse) {
+	// Nothing to do if the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
 		return
 	}
-	if validityEndTimeStr.IsNull() || validityEndTimeStr.IsUnknown() {
+
+	// Default `comment` to the comment of `public_key_openssh`, or the first key of `public_keys_openssh`, if it is known
+	commentPath := path.Root("comment")
+	var comment types.String
+	res.Diagnostics.Append(res.Plan.GetAttribute(ctx, commentPath, &comment)...)
+	if res.Diagnostics.HasError() {
 		return
 	}
+	if comment.IsUnknown() {
+		publicKeyPath := path.Root("public_key_openssh")
+		var publicKeyOpenSSH publicKeyOpenSSHValue
+		res.Diagnostics.Append(req.Plan.GetAttribute(ctx, publicKeyPath, &publicKeyOpenSSH)...)
+		if res.Diagnostics.HasError() {
+			return
+		}
+		if publicKeyOpenSSH.IsNull() {
+			var publicKeysOpenSSH types.List
+			res.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("public_keys_openssh"), &publicKeysOpenSSH)...)
+			if res.Diagnostics.HasError() {
+				return
+			}
+			if publicKeysOpenSSH.IsUnknown() {
+				return
+			}
+			if !publicKeysOpenSSH.IsNull() && len(publicKeysOpenSSH.Elements()) > 0 {
+				publicKeyPath = path.Root("public_keys_openssh").AtListIndex(0)
+				res.Diagnostics.Append(req.Plan.GetAttribute(ctx, publicKeyPath, &publicKeyOpenSSH)...)
+				if res.Diagnostics.HasError() {
+					return
+				}
+			}
+		}
+		if publicKeyOpenSSH.IsUnknown() {
+			return
+		}
+		// Keys given in `public_key_pem` have no comment
+		var parsedComment string
+		if !publicKeyOpenSSH.IsNull() {
+			var err error
+			_, parsedComment, err = parsePublicKeyOpenSSH(publicKeyOpenSSH.ValueString())
+			if err != nil {
+				res.Diagnostics.AddAttributeError(publicKeyPath, "Failed to parse public key", err.Error())
+				return
+			}
+		}
+		comment = types.StringValue(parsedComment)
+		res.Diagnostics.Append(res.Plan.SetAttribute(ctx, commentPath, comment)...)
+	}
 
-	// Parse `validity_end_time`
-	validityEndTime, err := time.Parse(time.RFC3339, validityEndTimeStr.ValueString())
+	// An existing certificate only needs its authorized key line updated with the new comment
+	if req.State.Raw.IsNull() || comment.IsUnknown() {
+		return
+	}
+	var certAuthorizedKey types.String
+	res.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("cert_authorized_key"), &certAuthorizedKey)...)
+	if res.Diagnostics.HasError() {
+		return
+	}
+	if certAuthorizedKey.IsNull() || certAuthorizedKey.IsUnknown() {
+		return
+	}
+	certificate, err := parseCertificate(certAuthorizedKey.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			fmt.Sprintf("Failed to parse data from string: %s", validityEndTimeStr.ValueString()),
-			err.Error(),
-		)
+		res.Diagnostics.AddError("Failed to parse certificate from state", err.Error())
 		return
 	}
+	res.Diagnostics.Append(res.Plan.SetAttribute(ctx, path.Root("cert_authorized_key"), marshalCertificate(certificate, comment.ValueString()))...)
 
-	// Retrieve `early_renewal_hours`
-	earlyRenewalHoursPath := path.Root("early_renewal_hours")
-	var earlyRenewalHours int64
-	resp.Diagnostics.Append(req.State.GetAttribute(ctx, earlyRenewalHoursPath, &earlyRenewalHours)...)
-	if resp.Diagnostics.HasError() {
+	var certAuthorizedKeys types.Map
+	res.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("cert_authorized_keys"), &certAuthorizedKeys)...)
+	if res.Diagnostics.HasError() {
 		return
 	}
+	// State written before `cert_authorized_keys` existed only holds the single certificate
+	certificates := []*ssh.Certificate{certificate}
+	if !certAuthorizedKeys.IsNull() && !certAuthorizedKeys.IsUnknown() {
+		certificates = nil
+		for _, v := range certAuthorizedKeys.Elements() {
+			vstr, ok := v.(types.String)
+			if !ok {
+				continue
+			}
+			c, err := parseCertificate(vstr.ValueString())
+			if err != nil {
+				res.Diagnostics.AddError("Failed to parse certificate from state", err.Error())
+				return
+			}
+			certificates = append(certificates, c)
+		}
+	}
+	certAuthorizedKeys, diags := certificatesToAuthorizedKeysMap(certificates, comment.ValueString())
+	res.Diagnostics.Append(diags...)
+	if res.Diagnostics.HasError() {
+		return
+	}
+	res.Diagnostics.Append(res.Plan.SetAttribute(ctx, path.Root("cert_authorized_keys"), certAuthorizedKeys)...)
+}
 
-	currentTime := overridableTimeFunc()
+// parseCertificate parses an SSH certificate in authorized keys format.
+func parseCertificate(certAuthorizedKey string) (*ssh.Certificate, error) {
+	pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(certAuthorizedKey))
+	if err != nil {
+		return nil, err
+	}
+	certificate, ok := pubKey.(*ssh.Certificate)
+	if !ok {
+		return nil, fmt.Errorf("expected an SSH certificate, got key of type %s", pubKey.Type())
+	}
+	return certificate, nil
+}
 
-	// Determine the time from which an "early renewal" is possible
-	earlyRenewalPeriod := time.Duration(-earlyRenewalHours) * time.Hour
-	earlyRenewalTime := validityEndTime.Add(earlyRenewalPeriod)
+// marshalCertificate serializes the certificate in authorized keys format, with an optional trailing comment.
+func marshalCertificate(certificate *ssh.Certificate, comment string) string {
+	authorizedKey := string(ssh.MarshalAuthorizedKey(certificate))
+	if comment == "" {
+		return authorizedKey
+	}
+	return strings.TrimSuffix(authorizedKey, "\n") + " " + comment + "\n"
+}
 
-	// If "early renewal" time has passed, mark it "ready for renewal"
-	timeToEarlyRenewal := earlyRenewalTime.Sub(currentTime)
-	if timeToEarlyRenewal <= 0 {
-		tflog.Info(ctx, "Certificate is ready for early renewal")
-		readyForRenewalPath := path.Root("ready_for_renewal")
-		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, readyForRenewalPath, true)...)
+// certificateBytesForSigning returns the portion of the certificate covered by its signature.
+func certificateBytesForSigning(certificate *ssh.Certificate) []byte {
+	c := *certificate
+	c.Signature = nil
+	out := c.Marshal()
+	// Drop the trailing length of the empty signature
+	return out[:len(out)-4]
+}
+
+// marshalCertificateBase64 serializes the certificate in base64 encoded SSH wire format.
+func marshalCertificateBase64(certificate *ssh.Certificate) string {
+	return base64.StdEncoding.EncodeToString(certificate.Marshal())
+}
+
+func certTypeToString(certType uint32) string {
+	switch certType {
+	case ssh.UserCert:
+		return "user"
+	case ssh.HostCert:
+		return "host"
+	default:
+		return fmt.Sprintf("unknown (%d)", certType)
+	}
+}
+
+// certificateDescription is the JSON representation of an ssh.Certificate.
+type certificateDescription struct {
+	Type                    string            `json:"type"`
+	KeyID                   string            `json:"key_id"`
+	Serial                  string            `json:"serial"`
+	PublicKey               string            `json:"public_key"`
+	PublicKeyFingerprint    string            `json:"public_key_fingerprint_sha256"`
+	SignatureKey            string            `json:"signature_key"`
+	SignatureKeyFingerprint string            `json:"signature_key_fingerprint_sha256"`
+	ValidPrincipals         []string          `json:"valid_principals"`
+	ValidAfter              string            `json:"valid_after"`
+	ValidBefore             string            `json:"valid_before"`
+	CriticalOptions         map[string]string `json:"critical_options"`
+	Extensions              map[string]string `json:"extensions"`
+}
+
+// marshalCertificateJSON describes the certificate as a JSON document.
+func marshalCertificateJSON(certificate *ssh.Certificate) (string, error) {
+	description := certificateDescription{
+		Type:                    certTypeToString(certificate.CertType),
+		KeyID:                   certificate.KeyId,
+		Serial:                  fmt.Sprintf("%d", certificate.Serial),
+		PublicKey:               strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(certificate.Key)), "\n"),
+		PublicKeyFingerprint:    ssh.FingerprintSHA256(certificate.Key),
+		SignatureKey:            strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(certificate.SignatureKey)), "\n"),
+		SignatureKeyFingerprint: ssh.FingerprintSHA256(certificate.SignatureKey),
+		ValidPrincipals:         certificate.ValidPrincipals,
+		ValidAfter:              time.Unix(int64(certificate.ValidAfter), 0).UTC().Format(time.RFC3339),
+		ValidBefore:             time.Unix(int64(certificate.ValidBefore), 0).UTC().Format(time.RFC3339),
+		CriticalOptions:         certificate.CriticalOptions,
+		Extensions:              certificate.Extensions,
+	}
+	if description.ValidPrincipals == nil {
+		description.ValidPrincipals = []string{}
+	}
+	if description.CriticalOptions == nil {
+		description.CriticalOptions = map[string]string{}
+	}
+	if description.Extensions == nil {
+		description.Extensions = map[string]string{}
+	}
+
+	b, err := json.Marshal(description)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// keyTypeToString returns the short key type name used by ssh-keygen, such as ED25519 or ECDSA-SK.
+func keyTypeToString(pubKey ssh.PublicKey) string {
+	switch pubKey.Type() {
+	case ssh.KeyAlgoRSA:
+		return "RSA"
+	case ssh.KeyAlgoECDSA256, ssh.KeyAlgoECDSA384, ssh.KeyAlgoECDSA521:
+		return "ECDSA"
+	case ssh.KeyAlgoSKECDSA256:
+		return "ECDSA-SK"
+	case ssh.KeyAlgoED25519:
+		return "ED25519"
+	case ssh.KeyAlgoSKED25519:
+		return "ED25519-SK"
+	default:
+		return strings.ToUpper(pubKey.Type())
+	}
+}
+
+// certificateValidityText formats the validity window of the certificate as ssh-keygen does.
+func certificateValidityText(certificate *ssh.Certificate) string {
+	const layout = "2006-01-02T15:04:05"
+	from := time.Unix(int64(certificate.ValidAfter), 0).UTC().Format(layout)
+	to := time.Unix(int64(certificate.ValidBefore), 0).UTC().Format(layout)
+
+	switch {
+	case certificate.ValidAfter == 0 && certificate.ValidBefore == math.MaxUint64:
+		return "forever"
+	case certificate.ValidAfter == 0:
+		return "before " + to
+	case certificate.ValidBefore == math.MaxUint64:
+		return "after " + from
+	default:
+		return fmt.Sprintf("from %s to %s", from, to)
+	}
+}
+
+// certificateOptionsText formats critical options or extensions as ssh-keygen does.
+func certificateOptionsText(options map[string]string) string {
+	if len(options) == 0 {
+		return " (none)\n"
+	}
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(" \n")
+	for _, name := range names {
+		if value := options[name]; value != "" {
+			fmt.Fprintf(&b, "        %s %s\n", name, value)
+		} else {
+			fmt.Fprintf(&b, "        %s\n", name)
+		}
+	}
+	return b.String()
+}
+
+// certificateText describes the certificate in the human-readable format of `ssh-keygen -L`.
+func certificateText(certificate *ssh.Certificate) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Type: %s %s certificate\n", certificate.Type(), certTypeToString(certificate.CertType))
+	fmt.Fprintf(&b, "Public key: %s-CERT %s\n", keyTypeToString(certificate.Key), ssh.FingerprintSHA256(certificate.Key))
+	signatureFormat := ""
+	if certificate.Signature != nil {
+		signatureFormat = certificate.Signature.Format
+	}
+	fmt.Fprintf(&b, "Signing CA: %s %s (using %s)\n", keyTypeToString(certificate.SignatureKey), ssh.FingerprintSHA256(certificate.SignatureKey), signatureFormat)
+	fmt.Fprintf(&b, "Key ID: %q\n", certificate.KeyId)
+	fmt.Fprintf(&b, "Serial: %d\n", certificate.Serial)
+	fmt.Fprintf(&b, "Valid: %s\n", certificateValidityText(certificate))
+
+	b.WriteString("Principals:")
+	if len(certificate.ValidPrincipals) == 0 {
+		b.WriteString(" (none)\n")
+	} else {
+		b.WriteString(" \n")
+		for _, principal := range certificate.ValidPrincipals {
+			fmt.Fprintf(&b, "        %s\n", principal)
+		}
 	}
+	b.WriteString("Critical Options:")
+	b.WriteString(certificateOptionsText(certificate.CriticalOptions))
+	b.WriteString("Extensions:")
+	b.WriteString(certificateOptionsText(certificate.Extensions))
+	return b.String()
 }
@@ -8,10 +8,15 @@ import (
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
 )
 
 type keyParser func([]byte) (crypto.PrivateKey, error)
@@ -26,6 +31,20 @@ var keyParsers = map[PEMPreamble]keyParser{
 	PreamblePrivateKeyPKCS8: func(der []byte) (crypto.PrivateKey, error) {
 		return x509.ParsePKCS8PrivateKey(der)
 	},
+	PreamblePrivateKeyOpenSSH: func(der []byte) (crypto.PrivateKey, error) {
+		prvKey, err := ssh.ParseRawPrivateKey(pem.EncodeToMemory(&pem.Block{
+			Type:  PreamblePrivateKeyOpenSSH.String(),
+			Bytes: der,
+		}))
+		if err != nil {
+			return nil, err
+		}
+		// Normalize to the same type returned by x509.ParsePKCS8PrivateKey
+		if ed25519PrvKey, ok := prvKey.(*ed25519.PrivateKey); ok {
+			return *ed25519PrvKey, nil
+		}
+		return prvKey, nil
+	},
 }
 
 func parsePrivateKeyPEM(keyPEMBytes []byte) (crypto.PrivateKey, Algorithm, error) {
@@ -73,3 +92,164 @@ func privateKeyToAlgorithm(prvKey crypto.PrivateKey) (Algorithm, error) {
 		return "", fmt.Errorf("unsupported private key type: %T", prvKey)
 	}
 }
+
+// sshECDSACurves are the ECDSA curves supported by SSH.
+var sshECDSACurves = map[ECDSACurve]elliptic.Curve{
+	P256: elliptic.P256(),
+	P384: elliptic.P384(),
+	P521: elliptic.P521(),
+}
+
+// generatePrivateKey generates a private key of the given algorithm. The RSA key size and
+// ECDSA curve only apply to keys of the respective algorithm.
+func generatePrivateKey(algorithm Algorithm, rsaBits int, ecdsaCurve ECDSACurve) (crypto.Signer, error) {
+	switch algorithm {
+	case RSA:
+		return rsa.GenerateKey(rand.Reader, rsaBits)
+	case ECDSA:
+		curve, ok := sshECDSACurves[ecdsaCurve]
+		if !ok {
+			return nil, fmt.Errorf("unsupported ECDSA curve: %s", ecdsaCurve)
+		}
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	case ED25519:
+		_, prvKey, err := ed25519.GenerateKey(rand.Reader)
+		return prvKey, err
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s", algorithm)
+	}
+}
+
+// marshalPrivateKeyOpenSSH serializes a private key in OpenSSH format, with an optional comment.
+func marshalPrivateKeyOpenSSH(prvKey crypto.PrivateKey, comment string) (string, error) {
+	pemBlock, err := ssh.MarshalPrivateKey(prvKey, comment)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(pemBlock)), nil
+}
+
+type publicKeyParser func([]byte) (crypto.PublicKey, error)
+
+var publicKeyParsers = map[PEMPreamble]publicKeyParser{
+	PreamblePublicKey: func(der []byte) (crypto.PublicKey, error) {
+		return x509.ParsePKIXPublicKey(der)
+	},
+	PreambleCertificate: func(der []byte) (crypto.PublicKey, error) {
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, err
+		}
+		return certificate.PublicKey, nil
+	},
+}
+
+// parsePublicKeyPEM parses a public key in PEM (SubjectPublicKeyInfo) format,
+// or the public key of an X.509 certificate in PEM format, and converts it to an ssh.PublicKey.
+func parsePublicKeyPEM(keyPEMBytes []byte) (ssh.PublicKey, error) {
+	pemBlock, rest := pem.Decode(keyPEMBytes)
+	if pemBlock == nil {
+		return nil, fmt.Errorf("failed to decode PEM block: decoded bytes %d, undecoded %d", len(keyPEMBytes)-len(rest), len(rest))
+	}
+
+	// Identify the PEM preamble from the block
+	preamble, err := pemBlockToPEMPreamble(pemBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	// Identify parser for the given PEM preamble
+	parser, ok := publicKeyParsers[preamble]
+	if !ok {
+		return nil, fmt.Errorf("unable to determine public key parser for PEM preamble: %s", preamble)
+	}
+
+	pubKey, err := parser(pemBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key given PEM preamble '%s': %w", preamble, err)
+	}
+	sshPubKey, err := ssh.NewPublicKey(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert public key of type %T: %w", pubKey, err)
+	}
+	return sshPubKey, nil
+}
+
+// parsePublicKeyOpenSSH parses a public key in authorized keys format, or in RFC 4716 format.
+// The comment of a key in RFC 4716 format is the value of its Comment header.
+func parsePublicKeyOpenSSH(key string) (ssh.PublicKey, string, error) {
+	if isRFC4716PublicKey(key) {
+		k, err := parseRFC4716PublicKey(key)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse RFC 4716 public key: %w", err)
+		}
+		comment, _ := k.header(rfc4716HeaderComment)
+		return k.key, comment, nil
+	}
+
+	pubKey, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+	if err != nil {
+		return nil, "", err
+	}
+	return pubKey, comment, nil
+}
+
+// parsePublicKeyOpenSSHOrPrivateKeyPEM parses a public key in authorized keys format,
+// or derives the public key from a private key in PEM or OpenSSH format.
+func parsePublicKeyOpenSSHOrPrivateKeyPEM(key string) (ssh.PublicKey, string, error) {
+	if pubKey, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(key)); err == nil {
+		return pubKey, comment, nil
+	}
+
+	prvKey, _, err := parsePrivateKeyPEM([]byte(key))
+	if err != nil {
+		return nil, "", fmt.Errorf("key is neither a public key in authorized keys format nor a private key: %w", err)
+	}
+	pubKey, err := privateKeyToPublicKey(prvKey)
+	if err != nil {
+		return nil, "", err
+	}
+	sshPubKey, err := ssh.NewPublicKey(pubKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return sshPubKey, "", nil
+}
+
+// privateKeyToPublicKey returns the public part of a crypto.PrivateKey.
+func privateKeyToPublicKey(prvKey crypto.PrivateKey) (crypto.PublicKey, error) {
+	signer, ok := prvKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type: %T", prvKey)
+	}
+	return signer.Public(), nil
+}
+
+// publicKeyToCurve returns the curve and size in bits of a crypto.PublicKey.
+// The curve is empty for non-ECDSA keys.
+func publicKeyToCurve(pubKey crypto.PublicKey) (ECDSACurve, int, error) {
+	switch k := pubKey.(type) {
+	case *rsa.PublicKey:
+		return "", k.N.BitLen(), nil
+	case *ecdsa.PublicKey:
+		params := k.Curve.Params()
+		return ECDSACurve(strings.ReplaceAll(params.Name, "-", "")), params.BitSize, nil
+	case ed25519.PublicKey:
+		return "", ed25519.PublicKeySize * 8, nil
+	default:
+		return "", 0, fmt.Errorf("unsupported public key type: %T", pubKey)
+	}
+}
+
+func publicKeyToAlgorithm(pubKey ssh.PublicKey) (Algorithm, error) {
+	switch pubKey.Type() {
+	case ssh.KeyAlgoRSA:
+		return RSA, nil
+	case ssh.KeyAlgoECDSA256, ssh.KeyAlgoECDSA384, ssh.KeyAlgoECDSA521, ssh.KeyAlgoSKECDSA256:
+		return ECDSA, nil
+	case ssh.KeyAlgoED25519, ssh.KeyAlgoSKED25519:
+		return ED25519, nil
+	default:
+		return "", fmt.Errorf("unsupported public key type: %s", pubKey.Type())
+	}
+}
@@ -0,0 +1,440 @@
+// Copyright (c) HashiCorp, Inc.
+
+// Binary Key Revocation List (KRL) format, as specified by
+// https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.krl
+
+package provider
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	krlMagic         uint64 = 0x5353484b524c0a00
+	krlFormatVersion uint32 = 1
+
+	krlSectionCertificates      byte = 1
+	krlSectionExplicitKey       byte = 2
+	krlSectionFingerprintSHA1   byte = 3
+	krlSectionSignature         byte = 4
+	krlSectionFingerprintSHA256 byte = 5
+
+	krlSectionCertSerialList   byte = 0x20
+	krlSectionCertSerialRange  byte = 0x21
+	krlSectionCertSerialBitmap byte = 0x22
+	krlSectionCertKeyID        byte = 0x23
+)
+
+// sha256FingerprintRegexp matches a fingerprint in the format of ssh.FingerprintSHA256.
+var sha256FingerprintRegexp = regexp.MustCompile(`^SHA256:[A-Za-z0-9+/]{43}=?$`)
+
+// krl describes the revocations of a Key Revocation List.
+type krl struct {
+	version            uint64
+	generatedDate      uint64
+	comment            string
+	certificates       []*krlCertificates
+	explicitKeys       [][]byte
+	fingerprintsSHA1   [][]byte
+	fingerprintsSHA256 [][]byte
+}
+
+// krlCertificates describes the certificates revoked for a CA.
+// An empty caKey applies to certificates signed by any CA.
+type krlCertificates struct {
+	caKey        []byte
+	serials      []uint64
+	serialRanges []krlSerialRange
+	keyIDs       []string
+}
+
+// krlSerialRange is an inclusive range of certificate serial numbers.
+type krlSerialRange struct {
+	min uint64
+	max uint64
+}
+
+// certificatesForCA returns the certificate revocations for the CA key blob, adding a new entry if needed.
+func (k *krl) certificatesForCA(caKey []byte) *krlCertificates {
+	for _, c := range k.certificates {
+		if bytes.Equal(c.caKey, caKey) {
+			return c
+		}
+	}
+	c := &krlCertificates{caKey: caKey}
+	k.certificates = append(k.certificates, c)
+	return c
+}
+
+// revokeKey revokes a public key. As with `ssh-keygen -k`, certificates are revoked by
+// serial number under their CA, or by key ID if they have no serial number.
+func (k *krl) revokeKey(pubKey ssh.PublicKey) {
+	certificate, ok := pubKey.(*ssh.Certificate)
+	if !ok {
+		k.explicitKeys = append(k.explicitKeys, pubKey.Marshal())
+		return
+	}
+	c := k.certificatesForCA(certificate.SignatureKey.Marshal())
+	if certificate.Serial != 0 {
+		c.serials = append(c.serials, certificate.Serial)
+	} else {
+		c.keyIDs = append(c.keyIDs, certificate.KeyId)
+	}
+}
+
+// sortedBlobs sorts and removes duplicates from the blobs.
+func sortedBlobs(blobs [][]byte) [][]byte {
+	blobs = slices.Clone(blobs)
+	slices.SortFunc(blobs, bytes.Compare)
+	return slices.CompactFunc(blobs, bytes.Equal)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return binary.BigEndian.AppendUint32(b, v)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return binary.BigEndian.AppendUint64(b, v)
+}
+
+func appendString(b []byte, s []byte) []byte {
+	b = appendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+func appendSection(b []byte, sectionType byte, data []byte) []byte {
+	b = append(b, sectionType)
+	return appendString(b, data)
+}
+
+// Marshal serializes the KRL in binary format. Revocations are sorted and duplicates removed.
+func (k *krl) Marshal() []byte {
+	var b []byte
+	b = appendUint64(b, krlMagic)
+	b = appendUint32(b, krlFormatVersion)
+	b = appendUint64(b, k.version)
+	b = appendUint64(b, k.generatedDate)
+	// flags
+	b = appendUint64(b, 0)
+	// reserved
+	b = appendString(b, nil)
+	b = appendString(b, []byte(k.comment))
+
+	for _, c := range k.certificates {
+		var data []byte
+		data = appendString(data, c.caKey)
+		// reserved
+		data = appendString(data, nil)
+
+		if serials := slices.Compact(slices.Sorted(slices.Values(c.serials))); len(serials) > 0 {
+			var section []byte
+			for _, serial := range serials {
+				section = appendUint64(section, serial)
+			}
+			data = appendSection(data, krlSectionCertSerialList, section)
+		}
+		serialRanges := slices.Clone(c.serialRanges)
+		slices.SortFunc(serialRanges, func(a, b krlSerialRange) int {
+			if a.min != b.min {
+				return cmp.Compare(a.min, b.min)
+			}
+			return cmp.Compare(a.max, b.max)
+		})
+		for _, r := range slices.Compact(serialRanges) {
+			var section []byte
+			section = appendUint64(section, r.min)
+			section = appendUint64(section, r.max)
+			data = appendSection(data, krlSectionCertSerialRange, section)
+		}
+		if keyIDs := slices.Compact(slices.Sorted(slices.Values(c.keyIDs))); len(keyIDs) > 0 {
+			var section []byte
+			for _, keyID := range keyIDs {
+				section = appendString(section, []byte(keyID))
+			}
+			data = appendSection(data, krlSectionCertKeyID, section)
+		}
+		b = appendSection(b, krlSectionCertificates, data)
+	}
+
+	for _, blobSection := range []struct {
+		sectionType byte
+		blobs       [][]byte
+	}{
+		{krlSectionExplicitKey, k.explicitKeys},
+		{krlSectionFingerprintSHA1, k.fingerprintsSHA1},
+		{krlSectionFingerprintSHA256, k.fingerprintsSHA256},
+	} {
+		if len(blobSection.blobs) == 0 {
+			continue
+		}
+		var data []byte
+		for _, blob := range sortedBlobs(blobSection.blobs) {
+			data = appendString(data, blob)
+		}
+		b = appendSection(b, blobSection.sectionType, data)
+	}
+	return b
+}
+
+// krlReader reads the primitive types of the KRL format.
+type krlReader struct {
+	b   []byte
+	err error
+}
+
+func (r *krlReader) read(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.b) < n {
+		r.err = fmt.Errorf("unexpected end of data")
+		return nil
+	}
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *krlReader) byte() byte {
+	if v := r.read(1); v != nil {
+		return v[0]
+	}
+	return 0
+}
+
+func (r *krlReader) uint32() uint32 {
+	if v := r.read(4); v != nil {
+		return binary.BigEndian.Uint32(v)
+	}
+	return 0
+}
+
+func (r *krlReader) uint64() uint64 {
+	if v := r.read(8); v != nil {
+		return binary.BigEndian.Uint64(v)
+	}
+	return 0
+}
+
+func (r *krlReader) string() []byte {
+	n := r.uint32()
+	if n > uint32(len(r.b)) {
+		r.err = fmt.Errorf("unexpected end of data")
+		return nil
+	}
+	return r.read(int(n))
+}
+
+func (r *krlReader) empty() bool {
+	return len(r.b) == 0 || r.err != nil
+}
+
+// parseKRL parses a KRL in binary format. As in OpenSSH, signatures are verified with the signature
+// keys embedded in the KRL, and cover all data up to and including their signature key.
+func parseKRL(b []byte) (*krl, error) {
+	r := &krlReader{b: b}
+	if magic := r.uint64(); r.err == nil && magic != krlMagic {
+		return nil, fmt.Errorf("invalid KRL magic: %x", magic)
+	}
+	if formatVersion := r.uint32(); r.err == nil && formatVersion != krlFormatVersion {
+		return nil, fmt.Errorf("unsupported KRL format version: %d", formatVersion)
+	}
+	k := &krl{
+		version:       r.uint64(),
+		generatedDate: r.uint64(),
+	}
+	// flags
+	r.uint64()
+	// reserved
+	r.string()
+	k.comment = string(r.string())
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to parse KRL header: %w", r.err)
+	}
+
+	signed := false
+	for !r.empty() {
+		sectionType := r.byte()
+		section := &krlReader{b: r.string()}
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to parse KRL section: %w", r.err)
+		}
+		if signed && sectionType != krlSectionSignature {
+			return nil, fmt.Errorf("unexpected KRL section type %d after signature", sectionType)
+		}
+
+		switch sectionType {
+		case krlSectionCertificates:
+			caKey := section.string()
+			// reserved
+			section.string()
+			if section.err != nil {
+				return nil, fmt.Errorf("failed to parse KRL certificates section: %w", section.err)
+			}
+			if len(caKey) == 0 {
+				caKey = nil
+			}
+			c := k.certificatesForCA(caKey)
+			if err := parseKRLCertificates(section, c); err != nil {
+				return nil, err
+			}
+		case krlSectionExplicitKey, krlSectionFingerprintSHA1, krlSectionFingerprintSHA256:
+			var blobs [][]byte
+			for !section.empty() {
+				blobs = append(blobs, section.string())
+			}
+			if section.err != nil {
+				return nil, fmt.Errorf("failed to parse KRL section %d: %w", sectionType, section.err)
+			}
+			switch sectionType {
+			case krlSectionExplicitKey:
+				k.explicitKeys = append(k.explicitKeys, blobs...)
+			case krlSectionFingerprintSHA1:
+				k.fingerprintsSHA1 = append(k.fingerprintsSHA1, blobs...)
+			case krlSectionFingerprintSHA256:
+				k.fingerprintsSHA256 = append(k.fingerprintsSHA256, blobs...)
+			}
+		case krlSectionSignature:
+			// The section holds the signature key, and is followed by the signature
+			signedData := b[:len(b)-len(r.b)]
+			signatureBlob := r.string()
+			if r.err != nil {
+				return nil, fmt.Errorf("failed to parse KRL signature: %w", r.err)
+			}
+			signed = true
+			signatureKey, err := ssh.ParsePublicKey(section.b)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse KRL signature key: %w", err)
+			}
+			signature := &ssh.Signature{}
+			if err := ssh.Unmarshal(signatureBlob, signature); err != nil {
+				return nil, fmt.Errorf("failed to parse KRL signature: %w", err)
+			}
+			if err := signatureKey.Verify(signedData, signature); err != nil {
+				return nil, fmt.Errorf("invalid KRL signature by %s: %w", ssh.FingerprintSHA256(signatureKey), err)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported KRL section type: %d", sectionType)
+		}
+	}
+	return k, nil
+}
+
+// parseKRLCertificates parses the subsections of a certificates section.
+func parseKRLCertificates(r *krlReader, c *krlCertificates) error {
+	for !r.empty() {
+		sectionType := r.byte()
+		section := &krlReader{b: r.string()}
+		if r.err != nil {
+			return fmt.Errorf("failed to parse KRL certificates section: %w", r.err)
+		}
+
+		switch sectionType {
+		case krlSectionCertSerialList:
+			for !section.empty() {
+				c.serials = append(c.serials, section.uint64())
+			}
+		case krlSectionCertSerialRange:
+			serialRange := krlSerialRange{min: section.uint64(), max: section.uint64()}
+			c.serialRanges = append(c.serialRanges, serialRange)
+		case krlSectionCertSerialBitmap:
+			offset := section.uint64()
+			bitmap := new(big.Int).SetBytes(section.string())
+			// Each bit set in the bitmap revokes the serial at the offset plus the bit position
+			for i := 0; i < bitmap.BitLen(); i++ {
+				if bitmap.Bit(i) == 0 {
+					continue
+				}
+				start := i
+				for i+1 < bitmap.BitLen() && bitmap.Bit(i+1) == 1 {
+					i++
+				}
+				if start == i {
+					c.serials = append(c.serials, offset+uint64(i))
+				} else {
+					c.serialRanges = append(c.serialRanges, krlSerialRange{min: offset + uint64(start), max: offset + uint64(i)})
+				}
+			}
+		case krlSectionCertKeyID:
+			for !section.empty() {
+				c.keyIDs = append(c.keyIDs, string(section.string()))
+			}
+		default:
+			return fmt.Errorf("unsupported KRL certificates section type: %d", sectionType)
+		}
+		if section.err != nil {
+			return fmt.Errorf("failed to parse KRL certificates section %d: %w", sectionType, section.err)
+		}
+	}
+	return nil
+}
+
+// isKeyRevoked checks a plain public key against the explicit keys and fingerprints of the KRL.
+func (k *krl) isKeyRevoked(pubKey ssh.PublicKey) bool {
+	blob := pubKey.Marshal()
+	sha1Hash := sha1.Sum(blob)
+	sha256Hash := sha256.Sum256(blob)
+	return slices.ContainsFunc(k.explicitKeys, func(b []byte) bool { return bytes.Equal(b, blob) }) ||
+		slices.ContainsFunc(k.fingerprintsSHA1, func(b []byte) bool { return bytes.Equal(b, sha1Hash[:]) }) ||
+		slices.ContainsFunc(k.fingerprintsSHA256, func(b []byte) bool { return bytes.Equal(b, sha256Hash[:]) })
+}
+
+// isCertificateRevoked checks a certificate against the certificates sections of the KRL, of its
+// CA and of any CA, as OpenSSH does.
+func (k *krl) isCertificateRevoked(certificate *ssh.Certificate) bool {
+	caKey := certificate.SignatureKey.Marshal()
+	for _, c := range k.certificates {
+		if c.caKey != nil && !bytes.Equal(c.caKey, caKey) {
+			continue
+		}
+		if slices.Contains(c.keyIDs, certificate.KeyId) {
+			return true
+		}
+		// Serial number 0 is the default of certificates issued without one, and is never revoked
+		if certificate.Serial == 0 {
+			continue
+		}
+		if slices.Contains(c.serials, certificate.Serial) {
+			return true
+		}
+		for _, r := range c.serialRanges {
+			if certificate.Serial >= r.min && certificate.Serial <= r.max {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isRevoked checks whether the KRL revokes a public key or certificate. As with `ssh-keygen -Q`,
+// a certificate is also revoked if its public key or its CA key is revoked.
+func (k *krl) isRevoked(pubKey ssh.PublicKey) bool {
+	certificate, ok := pubKey.(*ssh.Certificate)
+	if !ok {
+		return k.isKeyRevoked(pubKey)
+	}
+	return k.isKeyRevoked(certificate.Key) ||
+		k.isKeyRevoked(certificate.SignatureKey) ||
+		k.isCertificateRevoked(certificate)
+}
+
+// parseSHA256Fingerprint decodes a fingerprint in the `SHA256:...` format of ssh.FingerprintSHA256.
+func parseSHA256Fingerprint(fingerprint string) ([]byte, error) {
+	if !sha256FingerprintRegexp.MatchString(fingerprint) {
+		return nil, fmt.Errorf("invalid SHA256 fingerprint: %s", fingerprint)
+	}
+	encoded := strings.TrimSuffix(strings.TrimPrefix(fingerprint, "SHA256:"), "=")
+	return base64.RawStdEncoding.DecodeString(encoded)
+}
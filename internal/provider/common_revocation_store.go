@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// revocationStoreLockTimeout is how long to wait for another process to release the store.
+	revocationStoreLockTimeout = 30 * time.Second
+	// revocationStoreLockRetryInterval is how often to retry taking the lock.
+	revocationStoreLockRetryInterval = 100 * time.Millisecond
+)
+
+// revocationStore is a local file recording revoked certificates, one JSON encoded
+// revocationRecord per line. Records are only ever appended.
+//
+// Access is serialized with a lock file next to the store, created exclusively,
+// so that it works the same on all platforms.
+type revocationStore struct {
+	path string
+}
+
+// revocationRecord describes a revoked certificate.
+type revocationRecord struct {
+	Serial             string `json:"serial"`
+	KeyID              string `json:"key_id"`
+	CAPublicKeyOpenSSH string `json:"ca_public_key_openssh"`
+	CAKeyFingerprint   string `json:"ca_key_fingerprint_sha256"`
+	RevokedTime        string `json:"revoked_time"`
+}
+
+// lock takes the store lock, waiting up to revocationStoreLockTimeout for it to be released.
+// The returned function releases the lock.
+func (s *revocationStore) lock() (func(), error) {
+	lockPath := s.path + ".lock"
+	deadline := time.Now().Add(revocationStoreLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			f.Close()
+			return func() {
+				os.Remove(lockPath)
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock file %s; remove it if no other process is using the revocation store", lockPath)
+		}
+		time.Sleep(revocationStoreLockRetryInterval)
+	}
+}
+
+// Append adds a record to the store, creating the store if it does not exist.
+func (s *revocationStore) Append(record revocationRecord) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Records returns all records in the store, in the order they were added.
+// A store that does not exist yet has no records.
+func (s *revocationStore) Records() ([]revocationRecord, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []revocationRecord
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record revocationRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid record on line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
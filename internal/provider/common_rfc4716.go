@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// RFC 4716 (SSH2) public key file format.
+//
+// See https://datatracker.ietf.org/doc/html/rfc4716.
+const (
+	rfc4716BeginMarker = "---- BEGIN SSH2 PUBLIC KEY ----"
+	rfc4716EndMarker   = "---- END SSH2 PUBLIC KEY ----"
+
+	// rfc4716LineLength is the maximum length of a line, excluding the line terminator.
+	rfc4716LineLength = 72
+	// rfc4716Base64LineLength is the length of the lines of the encoded key, as written by `ssh-keygen -e`.
+	rfc4716Base64LineLength = 70
+
+	rfc4716HeaderComment = "Comment"
+	rfc4716HeaderSubject = "Subject"
+)
+
+// rfc4716Header is a header of an RFC 4716 public key.
+type rfc4716Header struct {
+	tag   string
+	value string
+}
+
+// rfc4716PublicKey is a public key in RFC 4716 format, with its headers.
+type rfc4716PublicKey struct {
+	key     ssh.PublicKey
+	headers []rfc4716Header
+}
+
+// header returns the value of the first header with the tag. Tags are case-insensitive.
+func (k *rfc4716PublicKey) header(tag string) (string, bool) {
+	for _, h := range k.headers {
+		if strings.EqualFold(h.tag, tag) {
+			return h.value, true
+		}
+	}
+	return "", false
+}
+
+// isRFC4716PublicKey reports whether the input looks like a public key in RFC 4716 format.
+func isRFC4716PublicKey(in string) bool {
+	return strings.HasPrefix(strings.TrimSpace(in), rfc4716BeginMarker)
+}
+
+// parseRFC4716PublicKey parses a public key in RFC 4716 format. Header values continued over
+// several lines are joined, and quotes around a header value are removed.
+func parseRFC4716PublicKey(in string) (*rfc4716PublicKey, error) {
+	in = strings.ReplaceAll(in, "\r\n", "\n")
+	in = strings.ReplaceAll(in, "\r", "\n")
+	lines := strings.Split(strings.TrimSpace(in), "\n")
+	if len(lines) < 3 || strings.TrimSpace(lines[0]) != rfc4716BeginMarker {
+		return nil, fmt.Errorf("missing %q line", rfc4716BeginMarker)
+	}
+	if strings.TrimSpace(lines[len(lines)-1]) != rfc4716EndMarker {
+		return nil, fmt.Errorf("missing %q line", rfc4716EndMarker)
+	}
+	lines = lines[1 : len(lines)-1]
+
+	k := &rfc4716PublicKey{}
+	i := 0
+	for ; i < len(lines) && strings.Contains(lines[i], ":"); i++ {
+		line := lines[i]
+		for strings.HasSuffix(line, `\`) {
+			i++
+			if i >= len(lines) {
+				return nil, fmt.Errorf("unterminated header continuation")
+			}
+			line = strings.TrimSuffix(line, `\`) + lines[i]
+		}
+
+		tag, value, _ := strings.Cut(line, ":")
+		if tag == "" || strings.ContainsAny(tag, " \t") {
+			return nil, fmt.Errorf("invalid header tag: %q", tag)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+			value = value[1 : len(value)-1]
+		}
+		k.headers = append(k.headers, rfc4716Header{tag: tag, value: value})
+	}
+
+	var body strings.Builder
+	for ; i < len(lines); i++ {
+		body.WriteString(strings.TrimSpace(lines[i]))
+	}
+	keyBytes, err := base64.StdEncoding.DecodeString(body.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode public key: %w", err)
+	}
+	k.key, err = ssh.ParsePublicKey(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// marshalRFC4716PublicKey serializes the public key in RFC 4716 format. As with `ssh-keygen -e`,
+// the Comment header value is quoted. Header lines longer than allowed are continued with a
+// backslash.
+func marshalRFC4716PublicKey(pubKey ssh.PublicKey, headers []rfc4716Header) string {
+	var b strings.Builder
+	b.WriteString(rfc4716BeginMarker + "\n")
+	for _, h := range headers {
+		value := h.value
+		if strings.EqualFold(h.tag, rfc4716HeaderComment) {
+			value = `"` + value + `"`
+		}
+		line := h.tag + ": " + value
+		for len(line) > rfc4716LineLength {
+			b.WriteString(line[:rfc4716LineLength-1] + "\\\n")
+			line = line[rfc4716LineLength-1:]
+		}
+		b.WriteString(line + "\n")
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(pubKey.Marshal())
+	for len(encoded) > rfc4716Base64LineLength {
+		b.WriteString(encoded[:rfc4716Base64LineLength] + "\n")
+		encoded = encoded[rfc4716Base64LineLength:]
+	}
+	b.WriteString(encoded + "\n")
+	b.WriteString(rfc4716EndMarker + "\n")
+	return b.String()
+}
@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHFP fingerprint types, as registered for RFC 4255 and RFC 6594.
+const (
+	sshfpFingerprintTypeSHA1   uint8 = 1
+	sshfpFingerprintTypeSHA256 uint8 = 2
+)
+
+// sshfpFingerprintTypes maps the fingerprint type names accepted in configuration to their numbers.
+var sshfpFingerprintTypes = map[string]uint8{
+	"sha1":   sshfpFingerprintTypeSHA1,
+	"sha256": sshfpFingerprintTypeSHA256,
+}
+
+// sshfpAlgorithms maps public key types to SSHFP algorithm numbers, as registered for
+// RFC 4255, RFC 6594 and RFC 7479.
+var sshfpAlgorithms = map[string]uint8{
+	ssh.KeyAlgoRSA:      1,
+	ssh.KeyAlgoDSA:      2,
+	ssh.KeyAlgoECDSA256: 3,
+	ssh.KeyAlgoECDSA384: 3,
+	ssh.KeyAlgoECDSA521: 3,
+	ssh.KeyAlgoED25519:  4,
+}
+
+// sshfpRecord is the data of an SSHFP resource record.
+type sshfpRecord struct {
+	algorithm       uint8
+	fingerprintType uint8
+	fingerprint     []byte
+}
+
+// newSSHFPRecord returns the SSHFP record of the host key for the fingerprint type.
+// The record of a certificate is that of its key, since SSHFP only applies to plain host keys.
+func newSSHFPRecord(pubKey ssh.PublicKey, fingerprintType uint8) (sshfpRecord, error) {
+	if certificate, ok := pubKey.(*ssh.Certificate); ok {
+		pubKey = certificate.Key
+	}
+	algorithm, ok := sshfpAlgorithms[pubKey.Type()]
+	if !ok {
+		return sshfpRecord{}, fmt.Errorf("unsupported key type for SSHFP: %s", pubKey.Type())
+	}
+
+	var fingerprint []byte
+	switch fingerprintType {
+	case sshfpFingerprintTypeSHA1:
+		sum := sha1.Sum(pubKey.Marshal())
+		fingerprint = sum[:]
+	case sshfpFingerprintTypeSHA256:
+		sum := sha256.Sum256(pubKey.Marshal())
+		fingerprint = sum[:]
+	default:
+		return sshfpRecord{}, fmt.Errorf("unsupported SSHFP fingerprint type: %d", fingerprintType)
+	}
+
+	return sshfpRecord{
+		algorithm:       algorithm,
+		fingerprintType: fingerprintType,
+		fingerprint:     fingerprint,
+	}, nil
+}
+
+// Fingerprint returns the fingerprint in lowercase hexadecimal.
+func (r sshfpRecord) Fingerprint() string {
+	return hex.EncodeToString(r.fingerprint)
+}
+
+// String returns the record data in presentation format, e.g. `4 2 <fingerprint>`.
+func (r sshfpRecord) String() string {
+	return fmt.Sprintf("%d %d %s", r.algorithm, r.fingerprintType, r.Fingerprint())
+}
+
+// ZoneLine returns the record as a zone file line, in the format printed by `ssh-keygen -r`.
+// The TTL is omitted when zero.
+func (r sshfpRecord) ZoneLine(hostname string, ttl int64) string {
+	fields := []string{hostname}
+	if ttl > 0 {
+		fields = append(fields, fmt.Sprintf("%d", ttl))
+	}
+	fields = append(fields, "IN", "SSHFP", r.String())
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &authorizedPrincipalsDataSource{}
+
+func NewAuthorizedPrincipalsDataSource() datasource.DataSource {
+	return &authorizedPrincipalsDataSource{}
+}
+
+// authorizedPrincipalsDataSource defines the data source implementation.
+type authorizedPrincipalsDataSource struct{}
+
+// authorizedPrincipalsDataSourceModel describes the data source data model.
+type authorizedPrincipalsDataSourceModel struct {
+	Users                types.Map    `tfsdk:"users"`
+	AuthorizedPrincipals types.Map    `tfsdk:"authorized_principals"`
+	ID                   types.String `tfsdk:"id"`
+}
+
+// authorizedPrincipalsUserModel describes the principals accepted for a local user.
+type authorizedPrincipalsUserModel struct {
+	Principals types.List   `tfsdk:"principals"`
+	From       types.List   `tfsdk:"from"`
+	Command    types.String `tfsdk:"command"`
+}
+
+func (d *authorizedPrincipalsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_authorized_principals"
+}
+
+func (d *authorizedPrincipalsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Generate SSH server `AuthorizedPrincipalsFile` content",
+
+		Attributes: map[string]schema.Attribute{
+			"users": schema.MapNestedAttribute{
+				Required: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"principals": schema.ListAttribute{
+							ElementType: types.StringType,
+							Required:    true,
+							Validators: []validator.List{
+								listvalidator.ValueStringsAre(principalValidator()),
+							},
+							Description: "List of certificate principals allowed to log in as the user.",
+						},
+						"from": schema.ListAttribute{
+							ElementType: types.StringType,
+							Optional:    true,
+							Validators: []validator.List{
+								listvalidator.ValueStringsAre(fromPatternValidator()),
+							},
+							Description: "List of host name or address patterns that logins are allowed from.",
+						},
+						"command": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								optionValueValidator(),
+							},
+							Description: "Command forced on login.",
+						},
+					},
+				},
+				Description: "Map of local user names to the principals accepted for them. " +
+					"`from` and `command` are written as options on each principal line.",
+			},
+			"authorized_principals": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Map of local user names to `AuthorizedPrincipalsFile` content.",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for this data source: the SHA1 checksum of all `authorized_principals`.",
+			},
+		},
+	}
+}
+
+func (d *authorizedPrincipalsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+}
+
+func (d *authorizedPrincipalsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data authorizedPrincipalsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	users := map[string]authorizedPrincipalsUserModel{}
+	resp.Diagnostics.Append(data.Users.ElementsAs(ctx, &users, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	names := make([]string, 0, len(users))
+	for name := range users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	authorizedPrincipals := map[string]string{}
+	checksum := sha1.New()
+	for _, name := range names {
+		user := users[name]
+		options := authorizedKeysOptions{
+			command: user.Command.ValueString(),
+		}
+		var principals []string
+		resp.Diagnostics.Append(user.Principals.ElementsAs(ctx, &principals, false)...)
+		if !user.From.IsNull() {
+			resp.Diagnostics.Append(user.From.ElementsAs(ctx, &options.from, false)...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		var lines strings.Builder
+		for _, principal := range principals {
+			if o := options.String(); o != "" {
+				lines.WriteString(o + " ")
+			}
+			lines.WriteString(principal + "\n")
+		}
+		authorizedPrincipals[name] = lines.String()
+		fmt.Fprintf(checksum, "%s\n%s", name, lines.String())
+	}
+
+	authorizedPrincipalsValue, diags := types.MapValueFrom(ctx, types.StringType, authorizedPrincipals)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.AuthorizedPrincipals = authorizedPrincipalsValue
+	data.ID = types.StringValue(fmt.Sprintf("%x", checksum.Sum(nil)))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
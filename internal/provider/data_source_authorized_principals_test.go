@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestDataSourceAuthorizedPrincipals(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []r.TestStep{
+			{
+				Config: providerConfig + `
+	data "ssh_authorized_principals" "test" {
+		users = {
+			"root" = {
+				principals = ["admin", "oncall"]
+			}
+			"deploy" = {
+				principals = ["ci"]
+				from       = ["10.0.0.0/8"]
+				command    = "/usr/local/bin/deploy"
+			}
+		}
+	}`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_authorized_principals.test", "authorized_principals.root", "admin\noncall\n"),
+					r.TestCheckResourceAttr("data.ssh_authorized_principals.test", "authorized_principals.deploy", `from="10.0.0.0/8",command="/usr/local/bin/deploy" ci`+"\n"),
+				),
+			},
+			{
+				Config: providerConfig + `
+	data "ssh_authorized_principals" "test" {
+		users = {
+			"root" = {
+				principals = ["admin oncall"]
+			}
+		}
+	}`,
+				ExpectError: regexp.MustCompile("must not contain whitespace, commas or double quotes"),
+			},
+		},
+	})
+}
@@ -0,0 +1,243 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &certificateDataSource{}
+
+func NewCertificateDataSource() datasource.DataSource {
+	return &certificateDataSource{}
+}
+
+// certificateDataSource defines the data source implementation.
+type certificateDataSource struct{}
+
+// certificateDataSourceModel describes the data source data model.
+type certificateDataSourceModel struct {
+	CertAuthorizedKey    types.String `tfsdk:"cert_authorized_key"`
+	CertType             types.String `tfsdk:"cert_type"`
+	KeyID                types.String `tfsdk:"key_id"`
+	Serial               types.String `tfsdk:"serial"`
+	ValidPrincipals      types.List   `tfsdk:"valid_principals"`
+	CriticalOptions      types.Map    `tfsdk:"critical_options"`
+	Extensions           types.Map    `tfsdk:"extensions"`
+	ValidityStartTime    types.String `tfsdk:"validity_start_time"`
+	ValidityEndTime      types.String `tfsdk:"validity_end_time"`
+	CurrentlyValid       types.Bool   `tfsdk:"currently_valid"`
+	CAKeyAlgorithm       types.String `tfsdk:"ca_key_algorithm"`
+	CAPublicKeyOpenSSH   types.String `tfsdk:"ca_public_key_openssh"`
+	CAKeyFingerprint     types.String `tfsdk:"ca_key_fingerprint_sha256"`
+	PublicKeyOpenSSH     types.String `tfsdk:"public_key_openssh"`
+	PublicKeyFingerprint types.String `tfsdk:"public_key_fingerprint_sha256"`
+	SubjectKeyAlgorithm  types.String `tfsdk:"subject_key_algorithm"`
+	CertFingerprint      types.String `tfsdk:"cert_fingerprint_sha256"`
+	ID                   types.String `tfsdk:"id"`
+}
+
+func (d *certificateDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certificate"
+}
+
+func (d *certificateDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Parse an existing SSH certificate",
+
+		Attributes: map[string]schema.Attribute{
+			"cert_authorized_key": schema.StringAttribute{
+				Required:    true,
+				Description: "SSH certificate to parse, in authorized keys format.",
+			},
+			"cert_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Type of the certificate: `user` or `host`.",
+			},
+			"key_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "User or host identifier for certificate.",
+			},
+			"serial": schema.StringAttribute{
+				Computed:    true,
+				Description: "The certificate serial number.",
+			},
+			"valid_principals": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "List of principals the certificate is valid for.",
+			},
+			"critical_options": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Map of critical options for certificate usage permissions.",
+			},
+			"extensions": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Map of extensions for certificate usage permissions.",
+			},
+			"validity_start_time": schema.StringAttribute{
+				Computed: true,
+				Description: "The time after which the certificate is valid, " +
+					"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp. " +
+					"Null if the certificate has no start time.",
+			},
+			"validity_end_time": schema.StringAttribute{
+				Computed: true,
+				Description: "The time until which the certificate is invalid, " +
+					"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp. " +
+					"Null if the certificate does not expire.",
+			},
+			"currently_valid": schema.BoolAttribute{
+				Computed: true,
+				Description: "Is the certificate signature valid, " +
+					"and the current time within the validity period of the certificate?",
+			},
+			"ca_key_algorithm": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the algorithm of the Certificate Authority (CA) key that signed the certificate.",
+			},
+			"ca_public_key_openssh": schema.StringAttribute{
+				Computed:    true,
+				Description: "Public key of the Certificate Authority (CA) that signed the certificate, in authorized keys format.",
+			},
+			"ca_key_fingerprint_sha256": schema.StringAttribute{
+				Computed: true,
+				Description: "SHA256 fingerprint of the Certificate Authority (CA) public key, " +
+					"in the format printed by `ssh-keygen -l`.",
+			},
+			"public_key_openssh": schema.StringAttribute{
+				Computed:    true,
+				Description: "SSH public key signed by the certificate, in authorized keys format.",
+			},
+			"public_key_fingerprint_sha256": schema.StringAttribute{
+				Computed: true,
+				Description: "SHA256 fingerprint of the signed public key, " +
+					"in the format printed by `ssh-keygen -l`.",
+			},
+			"subject_key_algorithm": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the algorithm of the signed public key.",
+			},
+			"cert_fingerprint_sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA256 fingerprint of the SSH certificate.",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for this data source: the certificate serial number.",
+			},
+		},
+	}
+}
+
+func (d *certificateDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+}
+
+func (d *certificateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data certificateDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	certificate, err := parseCertificate(data.CertAuthorizedKey.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("cert_authorized_key"), "Failed to parse certificate", err.Error())
+		return
+	}
+
+	validFrom, err := certificateTimeValue(certificate.ValidAfter, 0)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to serialize validity start time", err.Error())
+		return
+	}
+	validTo, err := certificateTimeValue(certificate.ValidBefore, ssh.CertTimeInfinity)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to serialize validity end time", err.Error())
+		return
+	}
+	caAlgorithm, err := publicKeyToAlgorithm(certificate.SignatureKey)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to determine CA key algorithm", err.Error())
+		return
+	}
+	subjectAlgorithm, err := publicKeyToAlgorithm(certificate.Key)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to determine public key algorithm", err.Error())
+		return
+	}
+
+	var diags diag.Diagnostics
+	data.ValidPrincipals, diags = types.ListValueFrom(ctx, types.StringType, certificate.ValidPrincipals)
+	resp.Diagnostics.Append(diags...)
+	data.CriticalOptions, diags = types.MapValueFrom(ctx, types.StringType, certificate.CriticalOptions)
+	resp.Diagnostics.Append(diags...)
+	data.Extensions, diags = types.MapValueFrom(ctx, types.StringType, certificate.Extensions)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(fmt.Sprintf("%d", certificate.Serial))
+	data.CertType = types.StringValue(certTypeToString(certificate.CertType))
+	data.KeyID = types.StringValue(certificate.KeyId)
+	data.Serial = types.StringValue(fmt.Sprintf("%d", certificate.Serial))
+	data.ValidityStartTime = validFrom
+	data.ValidityEndTime = validTo
+	data.CurrentlyValid = types.BoolValue(certificateCurrentlyValid(certificate))
+	data.CAKeyAlgorithm = types.StringValue(caAlgorithm.String())
+	data.CAPublicKeyOpenSSH = types.StringValue(string(ssh.MarshalAuthorizedKey(certificate.SignatureKey)))
+	data.CAKeyFingerprint = types.StringValue(ssh.FingerprintSHA256(certificate.SignatureKey))
+	data.PublicKeyOpenSSH = types.StringValue(string(ssh.MarshalAuthorizedKey(certificate.Key)))
+	data.PublicKeyFingerprint = types.StringValue(ssh.FingerprintSHA256(certificate.Key))
+	data.SubjectKeyAlgorithm = types.StringValue(subjectAlgorithm.String())
+	data.CertFingerprint = types.StringValue(ssh.FingerprintSHA256(certificate))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// certificateCurrentlyValid checks the certificate signature and validity period against the current time.
+// Critical options are accepted regardless of whether a server supports them.
+func certificateCurrentlyValid(certificate *ssh.Certificate) bool {
+	checker := &ssh.CertChecker{
+		Clock: overridableTimeFunc,
+	}
+	for option := range certificate.CriticalOptions {
+		checker.SupportedCriticalOptions = append(checker.SupportedCriticalOptions, option)
+	}
+	// Any of the principals of the certificate are accepted
+	principal := ""
+	if len(certificate.ValidPrincipals) > 0 {
+		principal = certificate.ValidPrincipals[0]
+	}
+	return checker.CheckCert(principal, certificate) == nil
+}
+
+// certificateTimeValue converts a certificate validity time to an RFC3339 timestamp,
+// or null if it is the unbounded value of the field: 0 for the start, ssh.CertTimeInfinity for the end.
+func certificateTimeValue(t uint64, unbounded uint64) (types.String, error) {
+	if t == unbounded {
+		return types.StringNull(), nil
+	}
+	if t > math.MaxInt64 {
+		return types.StringNull(), fmt.Errorf("time %d is out of range", t)
+	}
+	text, err := time.Unix(int64(t), 0).MarshalText()
+	if err != nil {
+		return types.StringNull(), err
+	}
+	return types.StringValue(string(text)), nil
+}
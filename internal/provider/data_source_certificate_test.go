@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestDataSourceCertificate(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 setTimeForTest("2023-01-01T12:00:00Z"),
+		Steps: []r.TestStep{
+			{
+				Config: userCertConfig(1, 0) + `
+	data "ssh_certificate" "test" {
+		cert_authorized_key = ssh_user_cert.test.cert_authorized_key
+	}`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_certificate.test", "cert_type", "user"),
+					r.TestCheckResourceAttr("data.ssh_certificate.test", "key_id", "testUser"),
+					r.TestCheckResourceAttr("data.ssh_certificate.test", "currently_valid", "true"),
+					r.TestCheckResourceAttr("data.ssh_certificate.test", "valid_principals.#", "2"),
+					r.TestCheckResourceAttr("data.ssh_certificate.test", "valid_principals.0", "test1.local"),
+					r.TestCheckResourceAttr("data.ssh_certificate.test", "critical_options.force-command", "/usr/bin/id"),
+					r.TestCheckResourceAttr("data.ssh_certificate.test", "extensions.%", "3"),
+					r.TestCheckResourceAttr("data.ssh_certificate.test", "subject_key_algorithm", "ECDSA"),
+					r.TestCheckResourceAttrPair("data.ssh_certificate.test", "serial", "ssh_user_cert.test", "id"),
+					r.TestCheckResourceAttrPair("data.ssh_certificate.test", "validity_start_time", "ssh_user_cert.test", "validity_start_time"),
+					r.TestCheckResourceAttrPair("data.ssh_certificate.test", "validity_end_time", "ssh_user_cert.test", "validity_end_time"),
+					r.TestCheckResourceAttrPair("data.ssh_certificate.test", "ca_public_key_openssh", "ssh_user_cert.test", "ca_public_key_openssh"),
+					r.TestCheckResourceAttrPair("data.ssh_certificate.test", "ca_key_fingerprint_sha256", "ssh_user_cert.test", "ca_key_fingerprint_sha256"),
+					r.TestCheckResourceAttrPair("data.ssh_certificate.test", "cert_fingerprint_sha256", "ssh_user_cert.test", "cert_fingerprint_sha256"),
+				),
+			},
+			{
+				PreConfig: setTimeForTest("2023-01-01T11:00:00Z"),
+				Config: userCertConfig(1, 0) + `
+	data "ssh_certificate" "test" {
+		cert_authorized_key = ssh_user_cert.test.cert_authorized_key
+	}`,
+				Check: r.TestCheckResourceAttr("data.ssh_certificate.test", "currently_valid", "false"),
+			},
+			{
+				Config: providerConfig + `
+	data "ssh_certificate" "test" {
+		cert_authorized_key = "` + inputPublicKeyOpenSSH + `"
+	}`,
+				ExpectError: regexp.MustCompile("expected an SSH certificate"),
+			},
+		},
+	})
+}
+
+func TestDataSourceCertificateForever(t *testing.T) {
+	caPrvKey, _, err := parsePrivateKeyPEM([]byte(inputPrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(caPrvKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(inputEd25519PublicKeyOpenSSH))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate := &ssh.Certificate{Key: pubKey, Serial: 1, CertType: ssh.UserCert, KeyId: "forever", ValidBefore: ssh.CertTimeInfinity}
+	if err := certificate.SignCert(rand.Reader, signer); err != nil {
+		t.Fatal(err)
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 setTimeForTest("2023-01-01T12:00:00Z"),
+		Steps: []r.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+	data "ssh_certificate" "test" {
+		cert_authorized_key = %q
+	}`, marshalCertificate(certificate, "")),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_certificate.test", "key_id", "forever"),
+					r.TestCheckResourceAttr("data.ssh_certificate.test", "currently_valid", "true"),
+					r.TestCheckNoResourceAttr("data.ssh_certificate.test", "validity_start_time"),
+					r.TestCheckNoResourceAttr("data.ssh_certificate.test", "validity_end_time"),
+				),
+			},
+		},
+	})
+}
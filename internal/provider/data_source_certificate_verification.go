@@ -0,0 +1,289 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &certificateVerificationDataSource{}
+
+func NewCertificateVerificationDataSource() datasource.DataSource {
+	return &certificateVerificationDataSource{}
+}
+
+// defaultSupportedCriticalOptions are the critical options understood by OpenSSH sshd,
+// other than `source-address` which is always verified.
+var defaultSupportedCriticalOptions = []string{
+	"force-command",
+	"verify-required",
+}
+
+// certificateVerificationDataSource defines the data source implementation.
+type certificateVerificationDataSource struct{}
+
+// certificateVerificationDataSourceModel describes the data source data model.
+type certificateVerificationDataSourceModel struct {
+	CertAuthorizedKey        types.String `tfsdk:"cert_authorized_key"`
+	TrustedCAKeys            types.Set    `tfsdk:"trusted_ca_keys"`
+	Principal                types.String `tfsdk:"principal"`
+	CertType                 types.String `tfsdk:"cert_type"`
+	Time                     types.String `tfsdk:"time"`
+	SourceAddress            types.String `tfsdk:"source_address"`
+	SupportedCriticalOptions types.List   `tfsdk:"supported_critical_options"`
+	Valid                    types.Bool   `tfsdk:"valid"`
+	FailureReasons           types.List   `tfsdk:"failure_reasons"`
+	ID                       types.String `tfsdk:"id"`
+}
+
+func (d *certificateVerificationDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certificate_verification"
+}
+
+func (d *certificateVerificationDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Verify an SSH certificate against trusted CA keys and a principal, " +
+			"performing the same checks as an SSH server accepting the certificate",
+
+		Attributes: map[string]schema.Attribute{
+			"cert_authorized_key": schema.StringAttribute{
+				Required:    true,
+				Description: "SSH certificate to verify, in authorized keys format.",
+			},
+			"trusted_ca_keys": schema.SetAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				Description: "Set of trusted Certificate Authority (CA) public keys, in authorized keys format.",
+			},
+			"principal": schema.StringAttribute{
+				Required:    true,
+				Description: "User or host name that the certificate is presented for.",
+			},
+			"cert_type": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("user", "host"),
+				},
+				Description: "Expected type of the certificate: `user` or `host`. " +
+					"If not set, the certificate type is not checked.",
+			},
+			"time": schema.StringAttribute{
+				Optional: true,
+				Description: "Time at which to verify the validity period of the certificate, " +
+					"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp. " +
+					"Defaults to the current time.",
+			},
+			"source_address": schema.StringAttribute{
+				Optional: true,
+				Description: "IP address of the connecting client, " +
+					"checked against the `source-address` critical option of the certificate.",
+			},
+			"supported_critical_options": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Critical options understood by the server. " +
+					"Certificates with any other critical option are rejected. " +
+					"`source-address` is always supported. " +
+					"(default: `[\"force-command\", \"verify-required\"]`)",
+			},
+			"valid": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Would the certificate be accepted?",
+			},
+			"failure_reasons": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "List of reasons the certificate would be rejected. Empty if `valid` is `true`.",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for this data source: the SHA256 fingerprint of the certificate.",
+			},
+		},
+	}
+}
+
+func (d *certificateVerificationDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+}
+
+func (d *certificateVerificationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data certificateVerificationDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	certificate, err := parseCertificate(data.CertAuthorizedKey.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("cert_authorized_key"), "Failed to parse certificate", err.Error())
+		return
+	}
+
+	var trustedCAKeyStrings []string
+	resp.Diagnostics.Append(data.TrustedCAKeys.ElementsAs(ctx, &trustedCAKeyStrings, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var trustedCAKeys []ssh.PublicKey
+	for _, k := range trustedCAKeyStrings {
+		caKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(k))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("trusted_ca_keys"), "Failed to parse CA public key", err.Error())
+			return
+		}
+		trustedCAKeys = append(trustedCAKeys, caKey)
+	}
+
+	checkTime := overridableTimeFunc()
+	if !data.Time.IsNull() {
+		checkTime, err = time.Parse(time.RFC3339, data.Time.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("time"), fmt.Sprintf("Failed to parse data from string: %s", data.Time.ValueString()), err.Error())
+			return
+		}
+	}
+
+	var sourceAddress net.IP
+	if !data.SourceAddress.IsNull() {
+		sourceAddress = net.ParseIP(data.SourceAddress.ValueString())
+		if sourceAddress == nil {
+			resp.Diagnostics.AddAttributeError(path.Root("source_address"), "Failed to parse IP address", data.SourceAddress.ValueString())
+			return
+		}
+	}
+
+	supportedCriticalOptions := defaultSupportedCriticalOptions
+	if !data.SupportedCriticalOptions.IsNull() {
+		supportedCriticalOptions = nil
+		resp.Diagnostics.Append(data.SupportedCriticalOptions.ElementsAs(ctx, &supportedCriticalOptions, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	failureReasons := verifyCertificate(certificate, certificateVerifyOptions{
+		trustedCAKeys:            trustedCAKeys,
+		principal:                data.Principal.ValueString(),
+		certType:                 data.CertType.ValueString(),
+		time:                     checkTime,
+		sourceAddress:            sourceAddress,
+		supportedCriticalOptions: supportedCriticalOptions,
+	})
+
+	failureReasonsValue, diags := types.ListValueFrom(ctx, types.StringType, failureReasons)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.FailureReasons = failureReasonsValue
+	data.Valid = types.BoolValue(len(failureReasons) == 0)
+	data.ID = types.StringValue(ssh.FingerprintSHA256(certificate))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// certificateVerifyOptions holds the parameters a certificate is verified against.
+type certificateVerifyOptions struct {
+	trustedCAKeys            []ssh.PublicKey
+	principal                string
+	certType                 string
+	time                     time.Time
+	sourceAddress            net.IP
+	supportedCriticalOptions []string
+}
+
+// verifyCertificate performs the checks of ssh.CertChecker and the source-address check of an SSH server,
+// returning the reasons the certificate would be rejected.
+// Unlike ssh.CertChecker, all checks are performed rather than stopping at the first failure.
+func verifyCertificate(certificate *ssh.Certificate, opts certificateVerifyOptions) []string {
+	failureReasons := []string{}
+
+	trusted := false
+	for _, caKey := range opts.trustedCAKeys {
+		if bytes.Equal(caKey.Marshal(), certificate.SignatureKey.Marshal()) {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		failureReasons = append(failureReasons, fmt.Sprintf("certificate signed by untrusted CA key %s", ssh.FingerprintSHA256(certificate.SignatureKey)))
+	}
+
+	if err := certificate.SignatureKey.Verify(certificateBytesForSigning(certificate), certificate.Signature); err != nil {
+		failureReasons = append(failureReasons, "certificate signature does not verify")
+	}
+
+	if opts.certType != "" && certTypeToString(certificate.CertType) != opts.certType {
+		failureReasons = append(failureReasons, fmt.Sprintf("certificate is a %s certificate, expected a %s certificate", certTypeToString(certificate.CertType), opts.certType))
+	}
+
+	unixNow := opts.time.Unix()
+	if after := int64(certificate.ValidAfter); after < 0 || unixNow < after {
+		failureReasons = append(failureReasons, "certificate is not yet valid")
+	}
+	if before := int64(certificate.ValidBefore); certificate.ValidBefore != ssh.CertTimeInfinity && (unixNow >= before || before < 0) {
+		failureReasons = append(failureReasons, "certificate has expired")
+	}
+
+	if len(certificate.ValidPrincipals) > 0 && !slices.Contains(certificate.ValidPrincipals, opts.principal) {
+		failureReasons = append(failureReasons, fmt.Sprintf("principal %q not in the set of valid principals for the certificate: %q", opts.principal, certificate.ValidPrincipals))
+	}
+
+	options := make([]string, 0, len(certificate.CriticalOptions))
+	for option := range certificate.CriticalOptions {
+		options = append(options, option)
+	}
+	slices.Sort(options)
+	for _, option := range options {
+		if option == "source-address" {
+			if reason := checkSourceAddress(opts.sourceAddress, certificate.CriticalOptions[option]); reason != "" {
+				failureReasons = append(failureReasons, reason)
+			}
+			continue
+		}
+		if !slices.Contains(opts.supportedCriticalOptions, option) {
+			failureReasons = append(failureReasons, fmt.Sprintf("unsupported critical option %q in certificate", option))
+		}
+	}
+
+	return failureReasons
+}
+
+// checkSourceAddress matches the address against the comma separated addresses and CIDR blocks
+// of a source-address critical option, returning the reason for rejection if it does not match.
+func checkSourceAddress(addr net.IP, sourceAddrs string) string {
+	if addr == nil {
+		return "no source address given, but source-address match required"
+	}
+	for _, sourceAddr := range strings.Split(sourceAddrs, ",") {
+		if allowedIP := net.ParseIP(sourceAddr); allowedIP != nil {
+			if allowedIP.Equal(addr) {
+				return ""
+			}
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(sourceAddr)
+		if err != nil {
+			return fmt.Sprintf("error parsing source-address restriction %q: %s", sourceAddr, err)
+		}
+		if ipNet.Contains(addr) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("source address %s is not allowed because of source-address restriction", addr)
+}
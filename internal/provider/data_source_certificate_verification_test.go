@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestDataSourceCertificateVerification(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 setTimeForTest("2023-01-01T12:00:00Z"),
+		Steps: []r.TestStep{
+			{
+				Config: userCertConfig(1, 0) + `
+	data "ssh_certificate_verification" "test" {
+		cert_authorized_key = ssh_user_cert.test.cert_authorized_key
+		trusted_ca_keys     = [ssh_user_cert.test.ca_public_key_openssh]
+		principal           = "test1.local"
+		cert_type           = "user"
+		supported_critical_options = [
+			"force-command",
+			"permit-port-forwarding",
+			"permit-pty",
+		]
+	}`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_certificate_verification.test", "valid", "true"),
+					r.TestCheckResourceAttr("data.ssh_certificate_verification.test", "failure_reasons.#", "0"),
+				),
+			},
+			{
+				Config: userCertConfig(1, 0) + fmt.Sprintf(`
+	data "ssh_certificate_verification" "test" {
+		cert_authorized_key = ssh_user_cert.test.cert_authorized_key
+		trusted_ca_keys     = [%q]
+		principal           = "test3.local"
+		cert_type           = "host"
+		time                = "2023-01-01T13:00:00Z"
+	}`, inputPublicKeyOpenSSH),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_certificate_verification.test", "valid", "false"),
+					r.TestCheckResourceAttr("data.ssh_certificate_verification.test", "failure_reasons.#", "6"),
+					r.TestCheckResourceAttr("data.ssh_certificate_verification.test", "failure_reasons.0", "certificate signed by untrusted CA key SHA256:2nVc0iddaYtYlD6re4rFLou3dJZePXO7TJ35QLCSVOA"),
+					r.TestCheckResourceAttr("data.ssh_certificate_verification.test", "failure_reasons.1", "certificate is a user certificate, expected a host certificate"),
+					r.TestCheckResourceAttr("data.ssh_certificate_verification.test", "failure_reasons.2", "certificate has expired"),
+					r.TestCheckResourceAttr("data.ssh_certificate_verification.test", "failure_reasons.3", `principal "test3.local" not in the set of valid principals for the certificate: ["test1.local" "test2.local"]`),
+					r.TestCheckResourceAttr("data.ssh_certificate_verification.test", "failure_reasons.4", `unsupported critical option "permit-port-forwarding" in certificate`),
+					r.TestCheckResourceAttr("data.ssh_certificate_verification.test", "failure_reasons.5", `unsupported critical option "permit-pty" in certificate`),
+				),
+			},
+		},
+	})
+}
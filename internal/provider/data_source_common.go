@@ -1,27 +0,0 @@
-// Copyright (c) HashiCorp, Inc.
-
-package provider
-
-import (
-	"context"
-
-	"github.com/hashicorp/terraform-plugin-framework/datasource"
-)
-
-// Ensure provider defined types fully satisfy framework interfaces.
-var _ datasource.DataSource = &commonDataSource{}
-
-// commonDataSource defines the data source implementation.
-type commonDataSource struct{}
-
-func (d *commonDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
-}
-
-func (d *commonDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
-}
-
-func (d *commonDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
-}
-
-func (d *commonDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-}
@@ -0,0 +1,270 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &knownHostsDataSource{}
+
+func NewKnownHostsDataSource() datasource.DataSource {
+	return &knownHostsDataSource{}
+}
+
+// Markers of known_hosts lines.
+const (
+	knownHostsMarkerCertAuthority = "@cert-authority"
+	knownHostsMarkerRevoked       = "@revoked"
+)
+
+// knownHostsPatternRegexp matches a single host name pattern.
+var knownHostsPatternRegexp = regexp.MustCompile(`^[^\s,]+$`)
+
+// knownHostsDataSource defines the data source implementation.
+type knownHostsDataSource struct{}
+
+// knownHostsDataSourceModel describes the data source data model.
+type knownHostsDataSourceModel struct {
+	CertAuthorities types.List   `tfsdk:"cert_authorities"`
+	HostKeys        types.List   `tfsdk:"host_keys"`
+	RevokedKeys     types.List   `tfsdk:"revoked_keys"`
+	HashHostnames   types.Bool   `tfsdk:"hash_hostnames"`
+	HashSaltKey     types.String `tfsdk:"hash_salt_key"`
+	Lines           types.List   `tfsdk:"lines"`
+	KnownHosts      types.String `tfsdk:"known_hosts"`
+	ID              types.String `tfsdk:"id"`
+}
+
+// knownHostsEntryModel describes a key and the hosts it applies to.
+type knownHostsEntryModel struct {
+	PublicKeyOpenSSH types.String `tfsdk:"public_key_openssh"`
+	Hosts            types.List   `tfsdk:"hosts"`
+}
+
+func knownHostsEntrySchema(description string, hostsRequired bool, hostsDescription string) schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Optional: true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"public_key_openssh": schema.StringAttribute{
+					Required:    true,
+					Description: "SSH public key, in authorized keys format.",
+				},
+				"hosts": schema.ListAttribute{
+					ElementType: types.StringType,
+					Required:    hostsRequired,
+					Optional:    !hostsRequired,
+					Validators: []validator.List{
+						listvalidator.SizeAtLeast(1),
+						listvalidator.ValueStringsAre(
+							stringvalidator.LengthAtLeast(1),
+							stringvalidator.RegexMatches(knownHostsPatternRegexp, "must not contain whitespace or commas"),
+						),
+					},
+					Description: hostsDescription,
+				},
+			},
+		},
+		Description: description,
+	}
+}
+
+func (d *knownHostsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_known_hosts"
+}
+
+func (d *knownHostsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Generate SSH known_hosts entries",
+
+		Attributes: map[string]schema.Attribute{
+			"cert_authorities": knownHostsEntrySchema(
+				"List of host Certificate Authority (CA) keys, written as `@cert-authority` lines.",
+				true,
+				"List of host name patterns that host certificates signed by the CA are accepted for, such as `*.example.com`.",
+			),
+			"host_keys": knownHostsEntrySchema(
+				"List of plain host keys.",
+				true,
+				"List of host names, addresses or patterns of the host. A port other than `22` may be given as `host:port`.",
+			),
+			"revoked_keys": knownHostsEntrySchema(
+				"List of revoked host or Certificate Authority (CA) keys, written as `@revoked` lines.",
+				false,
+				"List of host name patterns the key is revoked for. (default: `[\"*\"]`)",
+			),
+			"hash_hostnames": schema.BoolAttribute{
+				Optional: true,
+				Description: "Hash host names, as `ssh-keygen -H` and the `HashKnownHosts` option do. " +
+					"Patterns containing wildcards or negations cannot be hashed and are written as is. " +
+					"**Warning:** the salt is not random, so that the output is stable. Without `hash_salt_key`, it is " +
+					"derived from the host name and key only, so hashed lines are the same everywhere, and anyone " +
+					"with the file can check a guessed host name against them without the cost of a per-line random salt. (default: `false`)",
+			},
+			"hash_salt_key": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(16),
+					stringvalidator.AlsoRequires(path.MatchRoot("hash_hostnames")),
+				},
+				Description: "Secret mixed into the salt of hashed host names, e.g. the result of a `random_password` resource, " +
+					"so that the salts are stable but cannot be derived from the host name and key alone.",
+			},
+			"lines": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "List of known_hosts lines.",
+			},
+			"known_hosts": schema.StringAttribute{
+				Computed:    true,
+				Description: "Content of the known_hosts file.",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for this data source: the SHA1 checksum of `known_hosts`.",
+			},
+		},
+	}
+}
+
+func (d *knownHostsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+}
+
+func (d *knownHostsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data knownHostsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	hashHostnames := data.HashHostnames.ValueBool()
+	hashSaltKey := []byte(data.HashSaltKey.ValueString())
+
+	lines := []string{}
+	for _, section := range []struct {
+		attribute string
+		marker    string
+		entries   types.List
+	}{
+		{"cert_authorities", knownHostsMarkerCertAuthority, data.CertAuthorities},
+		{"host_keys", "", data.HostKeys},
+		{"revoked_keys", knownHostsMarkerRevoked, data.RevokedKeys},
+	} {
+		if section.entries.IsNull() {
+			continue
+		}
+		var entries []knownHostsEntryModel
+		resp.Diagnostics.Append(section.entries.ElementsAs(ctx, &entries, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for i, entry := range entries {
+			pubKey, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(entry.PublicKeyOpenSSH.ValueString()))
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root(section.attribute).AtListIndex(i).AtName("public_key_openssh"), "Failed to parse public key", err.Error())
+				return
+			}
+
+			hosts := []string{"*"}
+			if !entry.Hosts.IsNull() {
+				hosts = nil
+				resp.Diagnostics.Append(entry.Hosts.ElementsAs(ctx, &hosts, false)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+			}
+			if section.marker == "" {
+				for j, host := range hosts {
+					hosts[j] = knownhosts.Normalize(host)
+				}
+			}
+
+			lines = append(lines, knownHostsLines(section.marker, hosts, pubKey, comment, hashHostnames, hashSaltKey)...)
+		}
+	}
+
+	knownHosts := strings.Join(lines, "\n")
+	if len(lines) > 0 {
+		knownHosts += "\n"
+	}
+
+	linesValue, diags := types.ListValueFrom(ctx, types.StringType, lines)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Lines = linesValue
+	data.KnownHosts = types.StringValue(knownHosts)
+	data.ID = types.StringValue(fmt.Sprintf("%x", sha1.Sum([]byte(knownHosts))))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// knownHostsLines formats the known_hosts lines for a key. When hashing, each hashable host
+// gets its own line, and any patterns that cannot be hashed share a single line.
+func knownHostsLines(marker string, hosts []string, pubKey ssh.PublicKey, comment string, hashHostnames bool, hashSaltKey []byte) []string {
+	keyText := strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(pubKey)), "\n")
+	if comment != "" {
+		keyText += " " + comment
+	}
+	formatLine := func(hostField string) string {
+		if marker == "" {
+			return hostField + " " + keyText
+		}
+		return marker + " " + hostField + " " + keyText
+	}
+
+	if !hashHostnames {
+		return []string{formatLine(strings.Join(hosts, ","))}
+	}
+
+	var lines []string
+	var unhashed []string
+	for _, host := range hosts {
+		if strings.ContainsAny(host, "*?!") {
+			unhashed = append(unhashed, host)
+			continue
+		}
+		lines = append(lines, formatLine(hashKnownHostsHostname(host, pubKey, hashSaltKey)))
+	}
+	if len(unhashed) > 0 {
+		lines = append(lines, formatLine(strings.Join(unhashed, ",")))
+	}
+	return lines
+}
+
+// hashKnownHostsHostname hashes the host name in the `|1|salt|hash` format of `HashKnownHosts`.
+// Unlike knownhosts.HashHostname, the salt is derived from the host name and key rather than random,
+// keyed with saltKey if not empty.
+func hashKnownHostsHostname(hostname string, pubKey ssh.PublicKey, saltKey []byte) string {
+	saltHash := sha1.New()
+	if len(saltKey) > 0 {
+		saltHash = hmac.New(sha1.New, saltKey)
+	}
+	saltHash.Write([]byte(hostname))
+	saltHash.Write(pubKey.Marshal())
+	salt := saltHash.Sum(nil)
+
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte(hostname))
+	return "|1|" + base64.StdEncoding.EncodeToString(salt) + "|" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestDataSourceKnownHosts(t *testing.T) {
+	pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(inputPublicKeyOpenSSH))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []r.TestStep{
+			{
+				Config: knownHostsConfig(false),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_known_hosts.test", "lines.#", "3"),
+					r.TestCheckResourceAttr("data.ssh_known_hosts.test", "lines.0", "@cert-authority *.example.com,example.com "+inputCAPublicKeyOpenSSH),
+					r.TestCheckResourceAttr("data.ssh_known_hosts.test", "lines.1", "host1.example.com,[host2.example.com]:2222 "+inputPublicKeyOpenSSH+" host1"),
+					r.TestCheckResourceAttr("data.ssh_known_hosts.test", "lines.2", "@revoked * "+inputPublicKeyOpenSSH),
+					r.TestCheckResourceAttr("data.ssh_known_hosts.test", "known_hosts", "@cert-authority *.example.com,example.com "+inputCAPublicKeyOpenSSH+"\n"+
+						"host1.example.com,[host2.example.com]:2222 "+inputPublicKeyOpenSSH+" host1\n"+
+						"@revoked * "+inputPublicKeyOpenSSH+"\n"),
+				),
+			},
+			{
+				Config: knownHostsConfig(true),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_known_hosts.test", "lines.#", "5"),
+					r.TestMatchResourceAttr("data.ssh_known_hosts.test", "lines.0", regexp.MustCompile(`^@cert-authority \|1\|[^|]+\|[^ ]+ ecdsa-sha2-nistp521 `)),
+					r.TestCheckResourceAttr("data.ssh_known_hosts.test", "lines.1", "@cert-authority *.example.com "+inputCAPublicKeyOpenSSH),
+					r.TestMatchResourceAttr("data.ssh_known_hosts.test", "lines.2", regexp.MustCompile(`^\|1\|[^|]+\|[^ ]+ ecdsa-sha2-nistp521 `)),
+					r.TestMatchResourceAttr("data.ssh_known_hosts.test", "lines.3", regexp.MustCompile(`^\|1\|[^|]+\|[^ ]+ ecdsa-sha2-nistp521 `)),
+					r.TestCheckResourceAttr("data.ssh_known_hosts.test", "lines.4", "@revoked * "+inputPublicKeyOpenSSH),
+				),
+			},
+			{
+				Config: strings.Replace(knownHostsConfig(true), `hash_hostnames = true`, `hash_hostnames = true
+		hash_salt_key  = "0123456789abcdef"`, 1),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttrWith("data.ssh_known_hosts.test", "lines.2", func(value string) error {
+						hostField, _, _ := strings.Cut(value, " ")
+						if hostField == hashKnownHostsHostname("host1.example.com", pubKey, nil) {
+							return fmt.Errorf("salt not keyed with hash_salt_key: %s", value)
+						}
+						if hostField != hashKnownHostsHostname("host1.example.com", pubKey, []byte("0123456789abcdef")) {
+							return fmt.Errorf("incorrect hashed host name: %s", value)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func knownHostsConfig(hashHostnames bool) string {
+	return providerConfig + fmt.Sprintf(`
+	data "ssh_known_hosts" "test" {
+		cert_authorities = [
+			{
+				public_key_openssh = %[1]q
+				hosts              = ["*.example.com", "example.com"]
+			},
+		]
+		host_keys = [
+			{
+				public_key_openssh = "%[2]s host1"
+				hosts              = ["host1.example.com", "host2.example.com:2222"]
+			},
+		]
+		revoked_keys = [
+			{
+				public_key_openssh = %[2]q
+			},
+		]
+		hash_hostnames = %[3]t
+	}`, inputCAPublicKeyOpenSSH, inputPublicKeyOpenSSH, hashHostnames)
+}
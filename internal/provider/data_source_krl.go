@@ -0,0 +1,286 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &krlDataSource{}
+
+func NewKRLDataSource() datasource.DataSource {
+	return &krlDataSource{}
+}
+
+// krlSerialRangeAttrTypes are the attribute types of krlSerialRangeModel.
+var krlSerialRangeAttrTypes = map[string]attr.Type{
+	"min": types.StringType,
+	"max": types.StringType,
+}
+
+// krlCertificatesAttrTypes are the attribute types of krlCertificatesModel.
+var krlCertificatesAttrTypes = map[string]attr.Type{
+	"ca_public_key_openssh": types.StringType,
+	"serials":               types.ListType{ElemType: types.StringType},
+	"serial_ranges":         types.ListType{ElemType: types.ObjectType{AttrTypes: krlSerialRangeAttrTypes}},
+	"key_ids":               types.ListType{ElemType: types.StringType},
+}
+
+// krlDataSource defines the data source implementation.
+type krlDataSource struct{}
+
+// krlDataSourceModel describes the data source data model.
+type krlDataSourceModel struct {
+	KRLBase64                 types.String `tfsdk:"krl_base64"`
+	CheckKey                  types.String `tfsdk:"check_key"`
+	Version                   types.Int64  `tfsdk:"version"`
+	Comment                   types.String `tfsdk:"comment"`
+	GeneratedTime             types.String `tfsdk:"generated_time"`
+	Certificates              types.List   `tfsdk:"certificates"`
+	RevokedKeys               types.List   `tfsdk:"revoked_keys"`
+	RevokedFingerprintsSHA1   types.List   `tfsdk:"revoked_fingerprints_sha1"`
+	RevokedFingerprintsSHA256 types.List   `tfsdk:"revoked_fingerprints_sha256"`
+	Revoked                   types.Bool   `tfsdk:"revoked"`
+	ID                        types.String `tfsdk:"id"`
+}
+
+func (d *krlDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_krl"
+}
+
+func (d *krlDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Parse an OpenSSH Key Revocation List (KRL)",
+
+		Attributes: map[string]schema.Attribute{
+			"krl_base64": schema.StringAttribute{
+				Required: true,
+				Description: "KRL in base64 encoded binary format, as generated by `ssh-keygen -k`. " +
+					"Signatures in the KRL are verified with the signature keys embedded in it, " +
+					"which are not checked against any trusted key.",
+			},
+			"check_key": schema.StringAttribute{
+				Optional: true,
+				Description: "Public key or certificate to check against the KRL, in authorized keys format. " +
+					"The result is set in `revoked`.",
+			},
+			"version": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Version number of the KRL.",
+			},
+			"comment": schema.StringAttribute{
+				Computed:    true,
+				Description: "Comment embedded in the KRL.",
+			},
+			"generated_time": schema.StringAttribute{
+				Computed: true,
+				Description: "The time the KRL was generated, " +
+					"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+			},
+			"certificates": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ca_public_key_openssh": schema.StringAttribute{
+							Computed: true,
+							Description: "Public key of the Certificate Authority (CA) that signed the revoked certificates, " +
+								"in authorized keys format. Null if the revocations apply to certificates signed by any CA.",
+						},
+						"serials": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "List of revoked certificate serial numbers.",
+						},
+						"serial_ranges": schema.ListNestedAttribute{
+							Computed: true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"min": schema.StringAttribute{
+										Computed:    true,
+										Description: "First revoked serial number.",
+									},
+									"max": schema.StringAttribute{
+										Computed:    true,
+										Description: "Last revoked serial number.",
+									},
+								},
+							},
+							Description: "List of inclusive ranges of revoked certificate serial numbers.",
+						},
+						"key_ids": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "List of revoked certificate key IDs.",
+						},
+					},
+				},
+				Description: "List of revoked certificates, grouped by the Certificate Authority (CA) that signed them.",
+			},
+			"revoked_keys": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "List of revoked public keys, in authorized keys format.",
+			},
+			"revoked_fingerprints_sha1": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "List of SHA1 fingerprints of revoked public keys.",
+			},
+			"revoked_fingerprints_sha256": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "List of SHA256 fingerprints of revoked public keys, in the format printed by `ssh-keygen -l`.",
+			},
+			"revoked": schema.BoolAttribute{
+				Computed: true,
+				Description: "Is `check_key` revoked by the KRL? A certificate is also revoked if its public key " +
+					"or its Certificate Authority (CA) key is revoked. Null if `check_key` is not set.",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for this data source: the SHA256 checksum of the KRL.",
+			},
+		},
+	}
+}
+
+func (d *krlDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+}
+
+func (d *krlDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data krlDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	krlBytes, err := base64.StdEncoding.DecodeString(data.KRLBase64.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("krl_base64"), "Failed to decode KRL", err.Error())
+		return
+	}
+	k, err := parseKRL(krlBytes)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("krl_base64"), "Failed to parse KRL", err.Error())
+		return
+	}
+
+	data.Revoked = types.BoolNull()
+	if !data.CheckKey.IsNull() {
+		pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(data.CheckKey.ValueString()))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("check_key"), "Failed to parse public key", err.Error())
+			return
+		}
+		data.Revoked = types.BoolValue(k.isRevoked(pubKey))
+	}
+
+	certificates, diags := krlCertificatesToList(ctx, k.certificates)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var revokedKeys []string
+	for _, blob := range k.explicitKeys {
+		pubKey, err := ssh.ParsePublicKey(blob)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to parse revoked public key", err.Error())
+			return
+		}
+		revokedKeys = append(revokedKeys, strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(pubKey)), "\n"))
+	}
+	var fingerprintsSHA1 []string
+	for _, hash := range k.fingerprintsSHA1 {
+		fingerprintsSHA1 = append(fingerprintsSHA1, "SHA1:"+base64.RawStdEncoding.EncodeToString(hash))
+	}
+	var fingerprintsSHA256 []string
+	for _, hash := range k.fingerprintsSHA256 {
+		fingerprintsSHA256 = append(fingerprintsSHA256, "SHA256:"+base64.RawStdEncoding.EncodeToString(hash))
+	}
+
+	generatedTimeBytes, err := time.Unix(int64(k.generatedDate), 0).MarshalText()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to serialize generated time", err.Error())
+		return
+	}
+
+	data.Certificates = certificates
+	data.RevokedKeys, diags = types.ListValueFrom(ctx, types.StringType, revokedKeys)
+	resp.Diagnostics.Append(diags...)
+	data.RevokedFingerprintsSHA1, diags = types.ListValueFrom(ctx, types.StringType, fingerprintsSHA1)
+	resp.Diagnostics.Append(diags...)
+	data.RevokedFingerprintsSHA256, diags = types.ListValueFrom(ctx, types.StringType, fingerprintsSHA256)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Version = types.Int64Value(int64(k.version))
+	data.Comment = types.StringValue(k.comment)
+	data.GeneratedTime = types.StringValue(string(generatedTimeBytes))
+	data.ID = types.StringValue(fmt.Sprintf("%x", sha256.Sum256(krlBytes)))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// krlCertificatesToList converts the certificates sections of a KRL to a list of krlCertificatesModel.
+func krlCertificatesToList(ctx context.Context, certificates []*krlCertificates) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	models := []krlCertificatesModel{}
+	for _, c := range certificates {
+		model := krlCertificatesModel{
+			CAPublicKeyOpenSSH: types.StringNull(),
+		}
+		if c.caKey != nil {
+			caKey, err := ssh.ParsePublicKey(c.caKey)
+			if err != nil {
+				diags.AddError("Failed to parse CA public key", err.Error())
+				return types.ListNull(types.ObjectType{AttrTypes: krlCertificatesAttrTypes}), diags
+			}
+			model.CAPublicKeyOpenSSH = types.StringValue(strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(caKey)), "\n"))
+		}
+
+		serials := []string{}
+		for _, serial := range c.serials {
+			serials = append(serials, fmt.Sprintf("%d", serial))
+		}
+		serialRanges := []krlSerialRangeModel{}
+		for _, r := range c.serialRanges {
+			serialRanges = append(serialRanges, krlSerialRangeModel{
+				Min: types.StringValue(fmt.Sprintf("%d", r.min)),
+				Max: types.StringValue(fmt.Sprintf("%d", r.max)),
+			})
+		}
+		keyIDs := append([]string{}, c.keyIDs...)
+
+		var d diag.Diagnostics
+		model.Serials, d = types.ListValueFrom(ctx, types.StringType, serials)
+		diags.Append(d...)
+		model.SerialRanges, d = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: krlSerialRangeAttrTypes}, serialRanges)
+		diags.Append(d...)
+		model.KeyIDs, d = types.ListValueFrom(ctx, types.StringType, keyIDs)
+		diags.Append(d...)
+		models = append(models, model)
+	}
+	if diags.HasError() {
+		return types.ListNull(types.ObjectType{AttrTypes: krlCertificatesAttrTypes}), diags
+	}
+
+	list, d := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: krlCertificatesAttrTypes}, models)
+	diags.Append(d...)
+	return list, diags
+}
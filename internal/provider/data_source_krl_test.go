@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestDataSourceKRL(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 setTimeForTest("2023-01-01T12:00:00Z"),
+		Steps: []r.TestStep{
+			{
+				Config: krlDataSourceConfig(inputPublicKeyOpenSSH),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttrPair("data.ssh_krl.test", "id", "ssh_krl.test", "id"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "version", "3"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "comment", "test"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "generated_time", "2023-01-01T12:00:00Z"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "certificates.#", "2"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "certificates.0.ca_public_key_openssh", inputCAPublicKeyOpenSSH),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "certificates.0.serials.#", "2"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "certificates.0.serials.0", "1"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "certificates.0.serials.1", "100"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "certificates.0.serial_ranges.#", "1"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "certificates.0.serial_ranges.0.min", "1000"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "certificates.0.serial_ranges.0.max", "2000"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "certificates.0.key_ids.0", "testUser"),
+					r.TestCheckNoResourceAttr("data.ssh_krl.test", "certificates.1.ca_public_key_openssh"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "certificates.1.key_ids.0", "compromised"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "revoked_keys.#", "1"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "revoked_keys.0", inputPublicKeyOpenSSH),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "revoked_fingerprints_sha1.#", "0"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "revoked_fingerprints_sha256.0", "SHA256:2nVc0iddaYtYlD6re4rFLou3dJZePXO7TJ35QLCSVOA"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "revoked", "true"),
+				),
+			},
+			{
+				Config: krlDataSourceConfig(inputEd25519PublicKeyOpenSSH),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_krl.test", "revoked", "false"),
+				),
+			},
+			{
+				Config: providerConfig + `
+				data "ssh_krl" "test" {
+					krl_base64 = "bm90IGEga3Js"
+				}`,
+				ExpectError: regexp.MustCompile("Failed to parse KRL"),
+			},
+		},
+	})
+}
+
+// inputKRLBase64 was generated with `ssh-keygen -k -z 7`, revoking serial 42 and key ID signedUser of
+// the CA ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEUgamYd0R4J9Ee9qXIxqhk73DoRrJx1YfY72Ueze4Tj.
+const inputKRLBase64 = "U1NIS1JMCgAAAAABAAAAAAAAAAcAAAAAatVKmQAAAAAAAAAAAAAAAAAAAAABAAAAWwAAADMAAAALc3NoLWVkMjU1MTkAAAAgRSBqZh3RHgn0R72pcjGqGTvcOhGsnHVh9jvZR7N7hOMAAAAAIAAAAAgAAAAAAAAAKiMAAAAOAAAACnNpZ25lZFVzZXI="
+
+func TestDataSourceKRLSigned(t *testing.T) {
+	b, err := base64.StdEncoding.DecodeString(inputKRLBase64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caPrvKey, _, err := parsePrivateKeyPEM([]byte(inputPrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(caPrvKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The signature covers all data up to and including the signature key
+	b = appendSection(b, krlSectionSignature, signer.PublicKey().Marshal())
+	signature, err := signer.Sign(rand.Reader, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = appendString(b, ssh.Marshal(signature))
+	tampered := bytes.Clone(b)
+	// Revoke serial 43 instead of 42
+	tampered[bytes.Index(tampered, []byte{0, 0, 0, 0, 0, 0, 0, 42})+7] = 43
+
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []r.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				data "ssh_krl" "test" {
+					krl_base64 = %q
+				}`, base64.StdEncoding.EncodeToString(b)),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_krl.test", "version", "7"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "certificates.#", "1"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "certificates.0.serials.0", "42"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "certificates.0.key_ids.0", "signedUser"),
+				),
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				data "ssh_krl" "test" {
+					krl_base64 = %q
+				}`, base64.StdEncoding.EncodeToString(tampered)),
+				ExpectError: regexp.MustCompile("invalid KRL signature"),
+			},
+		},
+	})
+}
+
+func TestDataSourceKRLAnyCA(t *testing.T) {
+	k := &krl{version: 1}
+	k.certificatesForCA(nil).serialRanges = []krlSerialRange{{min: 0, max: 100}}
+	caPrvKey, _, err := parsePrivateKeyPEM([]byte(inputPrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(caPrvKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(inputEd25519PublicKeyOpenSSH))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := func(serial uint64) string {
+		certificate := &ssh.Certificate{Key: pubKey, Serial: serial, CertType: ssh.UserCert, ValidBefore: ssh.CertTimeInfinity}
+		if err := certificate.SignCert(rand.Reader, signer); err != nil {
+			t.Fatal(err)
+		}
+		return providerConfig + fmt.Sprintf(`
+				data "ssh_krl" "test" {
+					krl_base64 = %q
+					check_key  = %q
+				}`, base64.StdEncoding.EncodeToString(k.Marshal()), marshalCertificate(certificate, ""))
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []r.TestStep{
+			{
+				Config: config(42),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckNoResourceAttr("data.ssh_krl.test", "certificates.0.ca_public_key_openssh"),
+					r.TestCheckResourceAttr("data.ssh_krl.test", "revoked", "true"),
+				),
+			},
+			{
+				Config: config(0),
+				Check:  r.TestCheckResourceAttr("data.ssh_krl.test", "revoked", "false"),
+			},
+		},
+	})
+}
+
+func krlDataSourceConfig(checkKey string) string {
+	return krlConfig(`"1", "100"`) + fmt.Sprintf(`
+	data "ssh_krl" "test" {
+		krl_base64 = ssh_krl.test.krl_base64
+		check_key  = %q
+	}`, checkKey)
+}
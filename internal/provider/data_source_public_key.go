@@ -0,0 +1,234 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &publicKeyDataSource{}
+
+func NewPublicKeyDataSource() datasource.DataSource {
+	return &publicKeyDataSource{}
+}
+
+// publicKeyDataSource defines the data source implementation.
+type publicKeyDataSource struct{}
+
+// publicKeyDataSourceModel describes the data source data model.
+type publicKeyDataSourceModel struct {
+	PrivateKeyPEM           types.String `tfsdk:"private_key_pem"`
+	PublicKey               types.String `tfsdk:"public_key"`
+	Algorithm               types.String `tfsdk:"algorithm"`
+	KeyBits                 types.Int64  `tfsdk:"key_bits"`
+	ECDSACurve              types.String `tfsdk:"ecdsa_curve"`
+	PublicKeyOpenSSH        types.String `tfsdk:"public_key_openssh"`
+	PublicKeyPEM            types.String `tfsdk:"public_key_pem"`
+	PublicKeyRFC4716        types.String `tfsdk:"public_key_rfc4716"`
+	Comment                 types.String `tfsdk:"comment"`
+	Subject                 types.String `tfsdk:"subject"`
+	PublicKeyFingerprint    types.String `tfsdk:"public_key_fingerprint_sha256"`
+	PublicKeyFingerprintMD5 types.String `tfsdk:"public_key_fingerprint_md5"`
+	ID                      types.String `tfsdk:"id"`
+}
+
+func (d *publicKeyDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_public_key"
+}
+
+func (d *publicKeyDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Get the public key from a private key, or convert a public key between formats",
+
+		Attributes: map[string]schema.Attribute{
+			"private_key_pem": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("private_key_pem"), path.MatchRoot("public_key")),
+				},
+				Description: "Private key, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) " +
+					"or OpenSSH format. Exactly one of `private_key_pem` or `public_key` must be set.",
+			},
+			"public_key": schema.StringAttribute{
+				Optional: true,
+				Description: "Public key to convert, in authorized keys or " +
+					"[RFC 4716](https://datatracker.ietf.org/doc/html/rfc4716) (`---- BEGIN SSH2 PUBLIC KEY ----`) format.",
+			},
+			"algorithm": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the algorithm of the key.",
+			},
+			"key_bits": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Size of the key in bits.",
+			},
+			"ecdsa_curve": schema.StringAttribute{
+				Computed:    true,
+				Description: "Elliptic curve of the key, when `algorithm` is `ECDSA`.",
+			},
+			"public_key_openssh": schema.StringAttribute{
+				Computed:    true,
+				Description: "Public key, in authorized keys format, followed by `comment` if any.",
+			},
+			"public_key_pem": schema.StringAttribute{
+				Computed: true,
+				Description: "Public key, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) " +
+					"PKIX (SubjectPublicKeyInfo) format.",
+			},
+			"public_key_rfc4716": schema.StringAttribute{
+				Computed: true,
+				Description: "Public key, in [RFC 4716](https://datatracker.ietf.org/doc/html/rfc4716) format, " +
+					"with `Comment` and `Subject` headers if any.",
+			},
+			"comment": schema.StringAttribute{
+				Computed: true,
+				Description: "Comment of `public_key`, from the authorized keys line or the RFC 4716 `Comment` header. " +
+					"Empty for `private_key_pem`.",
+			},
+			"subject": schema.StringAttribute{
+				Computed:    true,
+				Description: "RFC 4716 `Subject` header of `public_key`, the login name of the key owner. Null if not present.",
+			},
+			"public_key_fingerprint_sha256": schema.StringAttribute{
+				Computed: true,
+				Description: "SHA256 fingerprint of the public key, " +
+					"in the format printed by `ssh-keygen -l`.",
+			},
+			"public_key_fingerprint_md5": schema.StringAttribute{
+				Computed: true,
+				Description: "MD5 fingerprint of the public key, " +
+					"as colon separated hex bytes.",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for this data source: the SHA256 fingerprint of the public key.",
+			},
+		},
+	}
+}
+
+func (d *publicKeyDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+}
+
+func (d *publicKeyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data publicKeyDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var (
+		sshPubKey ssh.PublicKey
+		pubKey    crypto.PublicKey
+		algorithm Algorithm
+		comment   string
+		headers   []rfc4716Header
+	)
+	data.Subject = types.StringNull()
+	if !data.PrivateKeyPEM.IsNull() {
+		prvKey, prvKeyAlgorithm, err := parsePrivateKeyPEM([]byte(data.PrivateKeyPEM.ValueString()))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("private_key_pem"), "Failed to parse private key PEM", err.Error())
+			return
+		}
+		pubKey, err = privateKeyToPublicKey(prvKey)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to get public key from private key", err.Error())
+			return
+		}
+		sshPubKey, err = ssh.NewPublicKey(pubKey)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to create SSH public key", err.Error())
+			return
+		}
+		algorithm = prvKeyAlgorithm
+	} else {
+		publicKey := data.PublicKey.ValueString()
+		if isRFC4716PublicKey(publicKey) {
+			k, err := parseRFC4716PublicKey(publicKey)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("public_key"), "Failed to parse RFC 4716 public key", err.Error())
+				return
+			}
+			sshPubKey = k.key
+			comment, _ = k.header(rfc4716HeaderComment)
+			if subject, ok := k.header(rfc4716HeaderSubject); ok {
+				data.Subject = types.StringValue(subject)
+				headers = append(headers, rfc4716Header{tag: rfc4716HeaderSubject, value: subject})
+			}
+		} else {
+			var err error
+			sshPubKey, comment, _, _, err = ssh.ParseAuthorizedKey([]byte(publicKey))
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("public_key"), "Failed to parse public key", err.Error())
+				return
+			}
+		}
+
+		cryptoPubKey, ok := sshPubKey.(ssh.CryptoPublicKey)
+		if !ok {
+			resp.Diagnostics.AddAttributeError(path.Root("public_key"), "Unsupported public key",
+				fmt.Sprintf("unsupported public key type: %s", sshPubKey.Type()))
+			return
+		}
+		pubKey = cryptoPubKey.CryptoPublicKey()
+		var err error
+		algorithm, err = publicKeyToAlgorithm(sshPubKey)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("public_key"), "Unsupported public key", err.Error())
+			return
+		}
+	}
+	if comment != "" {
+		headers = append(headers, rfc4716Header{tag: rfc4716HeaderComment, value: comment})
+	}
+
+	curve, bits, err := publicKeyToCurve(pubKey)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to determine public key size", err.Error())
+		return
+	}
+	pubKeyBytes, err := x509.MarshalPKIXPublicKey(pubKey)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to marshal public key", err.Error())
+		return
+	}
+
+	data.Algorithm = types.StringValue(algorithm.String())
+	data.KeyBits = types.Int64Value(int64(bits))
+	data.ECDSACurve = types.StringNull()
+	if curve != "" {
+		data.ECDSACurve = types.StringValue(curve.String())
+	}
+	publicKeyOpenSSH := string(ssh.MarshalAuthorizedKey(sshPubKey))
+	if comment != "" {
+		publicKeyOpenSSH = strings.TrimSuffix(publicKeyOpenSSH, "\n") + " " + comment + "\n"
+	}
+	data.PublicKeyOpenSSH = types.StringValue(publicKeyOpenSSH)
+	data.PublicKeyPEM = types.StringValue(string(pem.EncodeToMemory(&pem.Block{
+		Type:  PreamblePublicKey.String(),
+		Bytes: pubKeyBytes,
+	})))
+	data.PublicKeyRFC4716 = types.StringValue(marshalRFC4716PublicKey(sshPubKey, headers))
+	data.Comment = types.StringValue(comment)
+	data.PublicKeyFingerprint = types.StringValue(ssh.FingerprintSHA256(sshPubKey))
+	data.PublicKeyFingerprintMD5 = types.StringValue(ssh.FingerprintLegacyMD5(sshPubKey))
+	data.ID = types.StringValue(ssh.FingerprintSHA256(sshPubKey))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
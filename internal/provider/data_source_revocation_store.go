@@ -0,0 +1,214 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &revocationStoreDataSource{}
+var _ datasource.DataSourceWithConfigure = &revocationStoreDataSource{}
+
+func NewRevocationStoreDataSource() datasource.DataSource {
+	return &revocationStoreDataSource{}
+}
+
+// revocationRecordAttrTypes are the attribute types of revocationRecordModel.
+var revocationRecordAttrTypes = map[string]attr.Type{
+	"serial":                    types.StringType,
+	"key_id":                    types.StringType,
+	"ca_public_key_openssh":     types.StringType,
+	"ca_key_fingerprint_sha256": types.StringType,
+	"revoked_time":              types.StringType,
+}
+
+// revocationStoreCertificatesAttrTypes are the attribute types of revocationStoreCertificatesModel.
+var revocationStoreCertificatesAttrTypes = map[string]attr.Type{
+	"ca_public_key_openssh": types.StringType,
+	"serials":               types.ListType{ElemType: types.StringType},
+}
+
+// revocationStoreDataSource defines the data source implementation.
+type revocationStoreDataSource struct {
+	revocationStore *revocationStore
+}
+
+// revocationStoreDataSourceModel describes the data source data model.
+type revocationStoreDataSourceModel struct {
+	RevokedCertificates types.List   `tfsdk:"revoked_certificates"`
+	Certificates        types.List   `tfsdk:"certificates"`
+	ID                  types.String `tfsdk:"id"`
+}
+
+// revocationRecordModel describes a certificate recorded in the revocation store.
+type revocationRecordModel struct {
+	Serial             types.String `tfsdk:"serial"`
+	KeyID              types.String `tfsdk:"key_id"`
+	CAPublicKeyOpenSSH types.String `tfsdk:"ca_public_key_openssh"`
+	CAKeyFingerprint   types.String `tfsdk:"ca_key_fingerprint_sha256"`
+	RevokedTime        types.String `tfsdk:"revoked_time"`
+}
+
+// revocationStoreCertificatesModel describes the serial numbers revoked for a Certificate Authority (CA).
+type revocationStoreCertificatesModel struct {
+	CAPublicKeyOpenSSH types.String `tfsdk:"ca_public_key_openssh"`
+	Serials            types.List   `tfsdk:"serials"`
+}
+
+func (d *revocationStoreDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_revocation_store"
+}
+
+func (d *revocationStoreDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Read the certificates recorded in the provider `revocation_store_path` by resources with `revoke_on_destroy` set",
+
+		Attributes: map[string]schema.Attribute{
+			"revoked_certificates": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"serial": schema.StringAttribute{
+							Computed:    true,
+							Description: "Serial number of the certificate.",
+						},
+						"key_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Key ID of the certificate.",
+						},
+						"ca_public_key_openssh": schema.StringAttribute{
+							Computed:    true,
+							Description: "Public key of the Certificate Authority (CA) that signed the certificate, in authorized keys format.",
+						},
+						"ca_key_fingerprint_sha256": schema.StringAttribute{
+							Computed:    true,
+							Description: "SHA256 fingerprint of the Certificate Authority (CA) public key.",
+						},
+						"revoked_time": schema.StringAttribute{
+							Computed: true,
+							Description: "The time the certificate was destroyed, " +
+								"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+						},
+					},
+				},
+				Description: "List of revoked certificates, in the order they were recorded.",
+			},
+			"certificates": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ca_public_key_openssh": schema.StringAttribute{
+							Computed:    true,
+							Description: "Public key of the Certificate Authority (CA) that signed the revoked certificates, in authorized keys format.",
+						},
+						"serials": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "List of revoked certificate serial numbers.",
+						},
+					},
+				},
+				Description: "Revoked certificates grouped by the Certificate Authority (CA) that signed them, " +
+					"in the format of the `ssh_krl` `certificates` attribute without serial ranges or key IDs, " +
+					"which the store does not record.",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for this data source: the path of the revocation store.",
+			},
+		},
+	}
+}
+
+func (d *revocationStoreDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	store, ok := req.ProviderData.(*revocationStore)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *revocationStore, got: %T. Please report this issue to the provider developers.", req.ProviderData))
+		return
+	}
+	d.revocationStore = store
+}
+
+func (d *revocationStoreDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data revocationStoreDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if d.revocationStore == nil {
+		resp.Diagnostics.AddError("Revocation store not configured",
+			"Set the provider revocation_store_path to read revoked certificates.")
+		return
+	}
+	records, err := d.revocationStore.Records()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read revocation store", err.Error())
+		return
+	}
+
+	var caKeys []string
+	serialsByCA := map[string][]string{}
+	revokedCertificates := []revocationRecordModel{}
+	for _, record := range records {
+		caKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(record.CAPublicKeyOpenSSH))
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to parse CA public key of revoked certificate", err.Error())
+			return
+		}
+		serial, err := parseSerial(record.Serial)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to parse serial number of revoked certificate", err.Error())
+			return
+		}
+		caKeyText := strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(caKey)), "\n")
+		if _, ok := serialsByCA[caKeyText]; !ok {
+			caKeys = append(caKeys, caKeyText)
+		}
+		serialsByCA[caKeyText] = append(serialsByCA[caKeyText], fmt.Sprintf("%d", serial))
+
+		revokedCertificates = append(revokedCertificates, revocationRecordModel{
+			Serial:             types.StringValue(record.Serial),
+			KeyID:              types.StringValue(record.KeyID),
+			CAPublicKeyOpenSSH: types.StringValue(record.CAPublicKeyOpenSSH),
+			CAKeyFingerprint:   types.StringValue(record.CAKeyFingerprint),
+			RevokedTime:        types.StringValue(record.RevokedTime),
+		})
+	}
+
+	var diags diag.Diagnostics
+	certificateModels := []revocationStoreCertificatesModel{}
+	for _, caKeyText := range caKeys {
+		model := revocationStoreCertificatesModel{CAPublicKeyOpenSSH: types.StringValue(caKeyText)}
+		model.Serials, diags = types.ListValueFrom(ctx, types.StringType, serialsByCA[caKeyText])
+		resp.Diagnostics.Append(diags...)
+		certificateModels = append(certificateModels, model)
+	}
+	certificates, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: revocationStoreCertificatesAttrTypes}, certificateModels)
+	resp.Diagnostics.Append(diags...)
+	data.RevokedCertificates, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: revocationRecordAttrTypes}, revokedCertificates)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Certificates = certificates
+	data.ID = types.StringValue(d.revocationStore.path)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
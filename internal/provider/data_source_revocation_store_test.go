@@ -0,0 +1,36 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestDataSourceRevocationStore(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "revoked.jsonl")
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []r.TestStep{
+			{
+				Config: providerConfigWithRevocationStore(storePath) + `
+				data "ssh_revocation_store" "test" {
+				}`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_revocation_store.test", "id", storePath),
+					r.TestCheckResourceAttr("data.ssh_revocation_store.test", "revoked_certificates.#", "0"),
+					r.TestCheckResourceAttr("data.ssh_revocation_store.test", "certificates.#", "0"),
+				),
+			},
+			{
+				Config: providerConfig + `
+				data "ssh_revocation_store" "test" {
+				}`,
+				ExpectError: regexp.MustCompile("Revocation store not configured"),
+			},
+		},
+	})
+}
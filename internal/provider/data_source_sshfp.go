@@ -0,0 +1,236 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &sshfpDataSource{}
+
+func NewSSHFPDataSource() datasource.DataSource {
+	return &sshfpDataSource{}
+}
+
+// sshfpHostnameRegexp matches a DNS owner name, relative or fully qualified.
+var sshfpHostnameRegexp = regexp.MustCompile(`^[^\s]+$`)
+
+// sshfpRecordAttrTypes are the attribute types of sshfpRecordModel.
+var sshfpRecordAttrTypes = map[string]attr.Type{
+	"public_key_openssh": types.StringType,
+	"algorithm":          types.Int64Type,
+	"fingerprint_type":   types.Int64Type,
+	"fingerprint":        types.StringType,
+	"value":              types.StringType,
+	"zone_line":          types.StringType,
+}
+
+// sshfpDataSource defines the data source implementation.
+type sshfpDataSource struct{}
+
+// sshfpDataSourceModel describes the data source data model.
+type sshfpDataSourceModel struct {
+	PublicKeysOpenSSH types.List   `tfsdk:"public_keys_openssh"`
+	FingerprintTypes  types.List   `tfsdk:"fingerprint_types"`
+	Hostname          types.String `tfsdk:"hostname"`
+	TTL               types.Int64  `tfsdk:"ttl"`
+	Records           types.List   `tfsdk:"records"`
+	Values            types.List   `tfsdk:"values"`
+	ZoneLines         types.List   `tfsdk:"zone_lines"`
+	ID                types.String `tfsdk:"id"`
+}
+
+// sshfpRecordModel describes an SSHFP record.
+type sshfpRecordModel struct {
+	PublicKeyOpenSSH types.String `tfsdk:"public_key_openssh"`
+	Algorithm        types.Int64  `tfsdk:"algorithm"`
+	FingerprintType  types.Int64  `tfsdk:"fingerprint_type"`
+	Fingerprint      types.String `tfsdk:"fingerprint"`
+	Value            types.String `tfsdk:"value"`
+	ZoneLine         types.String `tfsdk:"zone_line"`
+}
+
+func (d *sshfpDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sshfp"
+}
+
+func (d *sshfpDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Generate SSHFP DNS records ([RFC 4255](https://tools.ietf.org/html/rfc4255), " +
+			"[RFC 6594](https://tools.ietf.org/html/rfc6594)) for SSH host keys",
+
+		Attributes: map[string]schema.Attribute{
+			"public_keys_openssh": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				Description: "List of host public keys, in authorized keys format. " +
+					"For host certificates, the records are generated for the certified key.",
+			},
+			"fingerprint_types": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.UniqueValues(),
+					listvalidator.ValueStringsAre(stringvalidator.OneOf("sha1", "sha256")),
+				},
+				Description: "List of fingerprint types to generate records for: `sha1` or `sha256`. " +
+					"(default: `[\"sha1\", \"sha256\"]`)",
+			},
+			"hostname": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(sshfpHostnameRegexp, "must be a non-empty DNS name without whitespace"),
+				},
+				Description: "Owner name of the records in `zone_lines`, such as `host.example.com.`.",
+			},
+			"ttl": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				Description: "TTL of the records in `zone_lines`, in seconds. Omitted from the lines if not set.",
+			},
+			"records": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"public_key_openssh": schema.StringAttribute{
+							Computed:    true,
+							Description: "Host public key the record is for, in authorized keys format.",
+						},
+						"algorithm": schema.Int64Attribute{
+							Computed:    true,
+							Description: "SSHFP algorithm number: `1` (RSA), `2` (DSA), `3` (ECDSA) or `4` (Ed25519).",
+						},
+						"fingerprint_type": schema.Int64Attribute{
+							Computed:    true,
+							Description: "SSHFP fingerprint type number: `1` (SHA-1) or `2` (SHA-256).",
+						},
+						"fingerprint": schema.StringAttribute{
+							Computed:    true,
+							Description: "Fingerprint of the key, in hexadecimal.",
+						},
+						"value": schema.StringAttribute{
+							Computed:    true,
+							Description: "Record data in presentation format, such as `4 2 <fingerprint>`.",
+						},
+						"zone_line": schema.StringAttribute{
+							Computed:    true,
+							Description: "Record as a zone file line. Null if `hostname` is not set.",
+						},
+					},
+				},
+				Description: "List of SSHFP records, for each key and fingerprint type.",
+			},
+			"values": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "List of record data in presentation format, as accepted by most DNS providers.",
+			},
+			"zone_lines": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "List of records as zone file lines, in the format printed by `ssh-keygen -r`. " +
+					"Null if `hostname` is not set.",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for this data source: the SHA1 checksum of `values`.",
+			},
+		},
+	}
+}
+
+func (d *sshfpDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+}
+
+func (d *sshfpDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data sshfpDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var publicKeys []string
+	resp.Diagnostics.Append(data.PublicKeysOpenSSH.ElementsAs(ctx, &publicKeys, false)...)
+	fingerprintTypeNames := []string{"sha1", "sha256"}
+	if !data.FingerprintTypes.IsNull() {
+		fingerprintTypeNames = nil
+		resp.Diagnostics.Append(data.FingerprintTypes.ElementsAs(ctx, &fingerprintTypeNames, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	records := []sshfpRecordModel{}
+	values := []string{}
+	var zoneLines []string
+	for i, publicKey := range publicKeys {
+		pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("public_keys_openssh").AtListIndex(i), "Failed to parse public key", err.Error())
+			return
+		}
+
+		for _, name := range fingerprintTypeNames {
+			record, err := newSSHFPRecord(pubKey, sshfpFingerprintTypes[name])
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("public_keys_openssh").AtListIndex(i), "Failed to generate SSHFP record", err.Error())
+				return
+			}
+
+			recordModel := sshfpRecordModel{
+				PublicKeyOpenSSH: types.StringValue(publicKey),
+				Algorithm:        types.Int64Value(int64(record.algorithm)),
+				FingerprintType:  types.Int64Value(int64(record.fingerprintType)),
+				Fingerprint:      types.StringValue(record.Fingerprint()),
+				Value:            types.StringValue(record.String()),
+				ZoneLine:         types.StringNull(),
+			}
+			if !data.Hostname.IsNull() {
+				zoneLine := record.ZoneLine(data.Hostname.ValueString(), data.TTL.ValueInt64())
+				recordModel.ZoneLine = types.StringValue(zoneLine)
+				zoneLines = append(zoneLines, zoneLine)
+			}
+			records = append(records, recordModel)
+			values = append(values, record.String())
+		}
+	}
+
+	recordsValue, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: sshfpRecordAttrTypes}, records)
+	resp.Diagnostics.Append(diags...)
+	data.Records = recordsValue
+	data.Values, diags = types.ListValueFrom(ctx, types.StringType, values)
+	resp.Diagnostics.Append(diags...)
+	data.ZoneLines = types.ListNull(types.StringType)
+	if !data.Hostname.IsNull() {
+		data.ZoneLines, diags = types.ListValueFrom(ctx, types.StringType, zoneLines)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.ID = types.StringValue(fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(values, "\n")))))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
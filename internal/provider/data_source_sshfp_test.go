@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestDataSourceSSHFP(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []r.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				data "ssh_sshfp" "test" {
+					public_keys_openssh = [%q, %q]
+					hostname            = "host.example.com."
+				}`, inputPublicKeyOpenSSH, inputEd25519PublicKeyOpenSSH),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "records.#", "4"),
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "records.0.public_key_openssh", inputPublicKeyOpenSSH),
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "records.0.algorithm", "3"),
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "records.0.fingerprint_type", "1"),
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "records.0.fingerprint", "9f0cbaef2d2260a45decec24f305d9790b9fd226"),
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "records.1.fingerprint_type", "2"),
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "records.3.algorithm", "4"),
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "values.#", "4"),
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "values.1", "3 2 ee20229ccf17d3217868f7c959d2e4e818a6247ece864bc5bfbd9f1dc754811f"),
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "values.3", "4 2 0ec2f2133ca0c21c6e9c32f823389875824b50caeab9d7c3be25be1dece83868"),
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "zone_lines.0", "host.example.com. IN SSHFP 3 1 9f0cbaef2d2260a45decec24f305d9790b9fd226"),
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "records.2.zone_line", "host.example.com. IN SSHFP 4 1 e11a2e881d6c2f0423d8a501e5a8405b7d7cdc41"),
+				),
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				data "ssh_sshfp" "test" {
+					public_keys_openssh = [%q]
+					fingerprint_types   = ["sha256"]
+				}`, inputEd25519PublicKeyOpenSSH),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "values.#", "1"),
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "values.0", "4 2 0ec2f2133ca0c21c6e9c32f823389875824b50caeab9d7c3be25be1dece83868"),
+					r.TestCheckNoResourceAttr("data.ssh_sshfp.test", "zone_lines"),
+					r.TestCheckNoResourceAttr("data.ssh_sshfp.test", "records.0.zone_line"),
+				),
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				data "ssh_sshfp" "test" {
+					public_keys_openssh = [%q]
+					hostname            = "host.example.com."
+					ttl                 = 3600
+					fingerprint_types   = ["sha256"]
+				}`, inputEd25519PublicKeyOpenSSH),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_sshfp.test", "zone_lines.0", "host.example.com. 3600 IN SSHFP 4 2 0ec2f2133ca0c21c6e9c32f823389875824b50caeab9d7c3be25be1dece83868"),
+				),
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				data "ssh_sshfp" "test" {
+					public_keys_openssh = [%q]
+					fingerprint_types   = ["md5"]
+				}`, inputEd25519PublicKeyOpenSSH),
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+		},
+	})
+}
@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &trustedUserCADataSource{}
+
+func NewTrustedUserCADataSource() datasource.DataSource {
+	return &trustedUserCADataSource{}
+}
+
+// trustedUserCADataSource defines the data source implementation.
+type trustedUserCADataSource struct{}
+
+// trustedUserCADataSourceModel describes the data source data model.
+type trustedUserCADataSourceModel struct {
+	CAKeys            types.List   `tfsdk:"ca_keys"`
+	Accounts          types.Map    `tfsdk:"accounts"`
+	TrustedUserCAKeys types.String `tfsdk:"trusted_user_ca_keys"`
+	AuthorizedKeys    types.Map    `tfsdk:"authorized_keys"`
+	ID                types.String `tfsdk:"id"`
+}
+
+// trustedUserCAAccountModel describes the authorized_keys options of an account.
+type trustedUserCAAccountModel struct {
+	Principals types.List   `tfsdk:"principals"`
+	From       types.List   `tfsdk:"from"`
+	Command    types.String `tfsdk:"command"`
+}
+
+func (d *trustedUserCADataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_trusted_user_ca"
+}
+
+func (d *trustedUserCADataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Generate SSH server trust configuration for user Certificate Authorities (CA)",
+
+		Attributes: map[string]schema.Attribute{
+			"ca_keys": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				Sensitive:   true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				Description: "List of user Certificate Authority (CA) keys. Each is either a public key in authorized keys format, " +
+					"or a private key in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) or OpenSSH format.",
+			},
+			"accounts": schema.MapNestedAttribute{
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"principals": schema.ListAttribute{
+							ElementType: types.StringType,
+							Required:    true,
+							Validators: []validator.List{
+								listvalidator.SizeAtLeast(1),
+								listvalidator.ValueStringsAre(principalValidator()),
+							},
+							Description: "List of certificate principals allowed to log in to the account.",
+						},
+						"from": schema.ListAttribute{
+							ElementType: types.StringType,
+							Optional:    true,
+							Validators: []validator.List{
+								listvalidator.ValueStringsAre(fromPatternValidator()),
+							},
+							Description: "List of host name or address patterns that logins are allowed from.",
+						},
+						"command": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								optionValueValidator(),
+							},
+							Description: "Command forced on login.",
+						},
+					},
+				},
+				Description: "Map of local account names to the options of their `cert-authority` authorized_keys lines.",
+			},
+			"trusted_user_ca_keys": schema.StringAttribute{
+				Computed:    true,
+				Description: "Content of the `TrustedUserCAKeys` file.",
+			},
+			"authorized_keys": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Map of local account names to `cert-authority` authorized_keys file content.",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for this data source: the SHA1 checksum of `trusted_user_ca_keys`.",
+			},
+		},
+	}
+}
+
+func (d *trustedUserCADataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+}
+
+func (d *trustedUserCADataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data trustedUserCADataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var caKeyStrings []string
+	resp.Diagnostics.Append(data.CAKeys.ElementsAs(ctx, &caKeyStrings, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	caKeys := make([]ssh.PublicKey, len(caKeyStrings))
+	caComments := make([]string, len(caKeyStrings))
+	var trustedUserCAKeys strings.Builder
+	for i, k := range caKeyStrings {
+		caKey, comment, err := parsePublicKeyOpenSSHOrPrivateKeyPEM(k)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ca_keys").AtListIndex(i), "Failed to parse CA key", err.Error())
+			return
+		}
+		caKeys[i] = caKey
+		caComments[i] = comment
+		trustedUserCAKeys.WriteString(authorizedKeysLine(authorizedKeysOptions{}, caKey, comment) + "\n")
+	}
+
+	accounts := map[string]trustedUserCAAccountModel{}
+	if !data.Accounts.IsNull() {
+		resp.Diagnostics.Append(data.Accounts.ElementsAs(ctx, &accounts, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	authorizedKeys := map[string]string{}
+	for name, account := range accounts {
+		options := authorizedKeysOptions{
+			certAuthority: true,
+			command:       account.Command.ValueString(),
+		}
+		resp.Diagnostics.Append(account.Principals.ElementsAs(ctx, &options.principals, false)...)
+		if !account.From.IsNull() {
+			resp.Diagnostics.Append(account.From.ElementsAs(ctx, &options.from, false)...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		var lines strings.Builder
+		for i, caKey := range caKeys {
+			lines.WriteString(authorizedKeysLine(options, caKey, caComments[i]) + "\n")
+		}
+		authorizedKeys[name] = lines.String()
+	}
+
+	authorizedKeysValue, diags := types.MapValueFrom(ctx, types.StringType, authorizedKeys)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.AuthorizedKeys = authorizedKeysValue
+	data.TrustedUserCAKeys = types.StringValue(trustedUserCAKeys.String())
+	data.ID = types.StringValue(fmt.Sprintf("%x", sha1.Sum([]byte(trustedUserCAKeys.String()))))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
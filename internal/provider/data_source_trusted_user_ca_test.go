@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestDataSourceTrustedUserCA(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []r.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+	data "ssh_trusted_user_ca" "test" {
+		ca_keys = [
+			<<EOT
+%s
+EOT
+			,
+			"%s ca@example",
+		]
+		accounts = {
+			"deploy" = {
+				principals = ["deploy", "ci"]
+				from       = ["10.0.0.0/8", "!10.0.0.1"]
+				command    = "/usr/bin/rsync --server \"$SSH_ORIGINAL_COMMAND\""
+			}
+			"root" = {
+				principals = ["admin"]
+			}
+		}
+	}`, inputPrivateKey, inputPublicKeyOpenSSH),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("data.ssh_trusted_user_ca.test", "trusted_user_ca_keys", inputCAPublicKeyOpenSSH+"\n"+
+						inputPublicKeyOpenSSH+" ca@example\n"),
+					r.TestCheckResourceAttr("data.ssh_trusted_user_ca.test", "authorized_keys.root",
+						`cert-authority,principals="admin" `+inputCAPublicKeyOpenSSH+"\n"+
+							`cert-authority,principals="admin" `+inputPublicKeyOpenSSH+" ca@example\n"),
+					r.TestCheckResourceAttr("data.ssh_trusted_user_ca.test", "authorized_keys.deploy",
+						`cert-authority,principals="deploy,ci",from="10.0.0.0/8,!10.0.0.1",command="/usr/bin/rsync --server \"$SSH_ORIGINAL_COMMAND\"" `+inputCAPublicKeyOpenSSH+"\n"+
+							`cert-authority,principals="deploy,ci",from="10.0.0.0/8,!10.0.0.1",command="/usr/bin/rsync --server \"$SSH_ORIGINAL_COMMAND\"" `+inputPublicKeyOpenSSH+" ca@example\n"),
+				),
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+	data "ssh_trusted_user_ca" "test" {
+		ca_keys = [%q]
+		accounts = {
+			"root" = {
+				principals = ["admin,root"]
+			}
+		}
+	}`, inputPublicKeyOpenSSH),
+				ExpectError: regexp.MustCompile("must not contain whitespace, commas or double quotes"),
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+	data "ssh_trusted_user_ca" "test" {
+		ca_keys = [%q]
+		accounts = {
+			"deploy" = {
+				principals = ["ci"]
+				command    = "printf '%%s\\n' \"$SSH_ORIGINAL_COMMAND\""
+			}
+		}
+	}`, inputPublicKeyOpenSSH),
+				Check: r.TestCheckResourceAttr("data.ssh_trusted_user_ca.test", "authorized_keys.deploy",
+					`cert-authority,principals="ci",command="printf '%s\n' \"$SSH_ORIGINAL_COMMAND\"" `+inputPublicKeyOpenSSH+"\n"),
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+	data "ssh_trusted_user_ca" "test" {
+		ca_keys = [%q]
+		accounts = {
+			"deploy" = {
+				principals = ["ci"]
+				command    = "C:\\deploy\\"
+			}
+		}
+	}`, inputPublicKeyOpenSSH),
+				ExpectError: regexp.MustCompile("must not contain line breaks or end with a backslash"),
+			},
+		},
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &certTextFunction{}
+
+func NewCertTextFunction() function.Function {
+	return &certTextFunction{}
+}
+
+type certTextFunction struct{}
+
+func (f *certTextFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "cert_text"
+}
+
+func (f *certTextFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Describe an SSH certificate",
+		Description: "Returns a human-readable description of an SSH certificate, in the format printed by `ssh-keygen -L`.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "cert",
+				Description: "SSH certificate, in authorized keys format.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *certTextFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var cert string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &cert))
+	if resp.Error != nil {
+		return
+	}
+
+	certificate, err := parseCertificate(cert)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Failed to parse certificate: "+err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, certificateText(certificate)))
+}
@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestFunctionCertText(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 setTimeForTest("2023-01-01T12:00:00Z"),
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []r.TestStep{
+			{
+				Config: userCertConfig(1, 0) + `
+	output "test" {
+		value = provider::ssh::cert_text(ssh_user_cert.test.cert_authorized_key)
+	}`,
+				Check: func(s *terraform.State) error {
+					expected := s.RootModule().Resources["ssh_user_cert.test"].Primary.Attributes["cert_text"]
+					return r.TestCheckOutput("test", expected)(s)
+				},
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+	output "test" {
+		value = provider::ssh::cert_text(%q)
+	}`, inputPublicKeyOpenSSH),
+				ExpectError: regexp.MustCompile("expected an SSH certificate"),
+			},
+		},
+	})
+}
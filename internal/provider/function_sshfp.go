@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &sshfpFunction{}
+
+func NewSSHFPFunction() function.Function {
+	return &sshfpFunction{}
+}
+
+type sshfpFunction struct{}
+
+func (f *sshfpFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "sshfp"
+}
+
+func (f *sshfpFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Generate SSHFP DNS records for an SSH host key",
+		Description: "Returns the SHA-1 and SHA-256 SSHFP record data of an SSH host key, in presentation format, " +
+			"such as `4 2 <fingerprint>`. For host certificates, the records are generated for the certified key.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "public_key_openssh",
+				Description: "Host public key, in authorized keys format.",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *sshfpFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var publicKey string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &publicKey))
+	if resp.Error != nil {
+		return
+	}
+
+	pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Failed to parse public key: "+err.Error())
+		return
+	}
+
+	var values []string
+	for _, fingerprintType := range []uint8{sshfpFingerprintTypeSHA1, sshfpFingerprintTypeSHA256} {
+		record, err := newSSHFPRecord(pubKey, fingerprintType)
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(0, "Failed to generate SSHFP record: "+err.Error())
+			return
+		}
+		values = append(values, record.String())
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, values))
+}
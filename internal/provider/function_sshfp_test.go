@@ -0,0 +1,38 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestFunctionSSHFP(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []r.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+	output "test" {
+		value = join(",", provider::ssh::sshfp(%q))
+	}`, inputEd25519PublicKeyOpenSSH),
+				Check: r.TestCheckOutput("test", "4 1 e11a2e881d6c2f0423d8a501e5a8405b7d7cdc41,"+
+					"4 2 0ec2f2133ca0c21c6e9c32f823389875824b50caeab9d7c3be25be1dece83868"),
+			},
+			{
+				Config: providerConfig + `
+	output "test" {
+		value = provider::ssh::sshfp("not a key")
+	}`,
+				ExpectError: regexp.MustCompile("Failed to parse public key"),
+			},
+		},
+	})
+}
@@ -6,13 +6,17 @@ import (
 	"context"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure sshProvider satisfies various provider interfaces.
 var _ provider.Provider = &sshProvider{}
+var _ provider.ProviderWithFunctions = &sshProvider{}
 
 // sshProvider defines the provider implementation.
 type sshProvider struct {
@@ -24,6 +28,7 @@ type sshProvider struct {
 
 // sshProviderModel describes the provider data model.
 type sshProviderModel struct {
+	RevocationStorePath types.String `tfsdk:"revocation_store_path"`
 }
 
 func (p *sshProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -32,7 +37,17 @@ func (p *sshProvider) Metadata(ctx context.Context, req provider.MetadataRequest
 }
 
 func (p *sshProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
-	resp.Schema = schema.Schema{}
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"revocation_store_path": schema.StringAttribute{
+				Optional: true,
+				Description: "Path to a local file recording revoked certificates. " +
+					"Certificate resources with `revoke_on_destroy` set append to it when destroyed or replaced, " +
+					"and the `ssh_revocation_store` data source reads it back to generate a KRL with `ssh_krl`. " +
+					"The file is locked with a `.lock` file next to it while in use.",
+			},
+		},
+	}
 }
 
 func (p *sshProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
@@ -43,17 +58,51 @@ func (p *sshProvider) Configure(ctx context.Context, req provider.ConfigureReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	if data.RevocationStorePath.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("revocation_store_path"), "Unknown revocation store path",
+			"The revocation store path must be known when the provider is configured.")
+		return
+	}
+	if !data.RevocationStorePath.IsNull() {
+		store := &revocationStore{
+			path: data.RevocationStorePath.ValueString(),
+		}
+		resp.ResourceData = store
+		resp.DataSourceData = store
+	}
 }
 
 func (p *sshProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewHostCertResource,
+		NewHostCertsResource,
+		NewHostIdentityResource,
+		NewKRLResource,
 		NewUserCertResource,
+		NewUserIdentityResource,
 	}
 }
 
 func (p *sshProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewAuthorizedPrincipalsDataSource,
+		NewCertificateDataSource,
+		NewCertificateVerificationDataSource,
+		NewKnownHostsDataSource,
+		NewKRLDataSource,
+		NewTrustedUserCADataSource,
+		NewPublicKeyDataSource,
+		NewRevocationStoreDataSource,
+		NewSSHFPDataSource,
+	}
+}
+
+func (p *sshProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewCertTextFunction,
+		NewSSHFPFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
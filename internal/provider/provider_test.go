@@ -3,6 +3,8 @@
 package provider
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
@@ -25,3 +27,12 @@ provider "ssh" {
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
 	"ssh": providerserver.NewProtocol6WithError(New("test")()),
 }
+
+// providerConfigWithRevocationStore is providerConfig with the revocation store set to path.
+func providerConfigWithRevocationStore(path string) string {
+	return fmt.Sprintf(`
+provider "ssh" {
+	revocation_store_path = %q
+}
+`, path)
+}
@@ -7,10 +7,14 @@ import (
 	"crypto/rand"
 	"fmt"
 	"golang.org/x/crypto/ssh"
+	"math"
 	"math/big"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -25,55 +29,111 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/randomcoww/terraform-provider-ssh/internal/provider/attribute_plan_modifier_bool"
 )
 
 // commonCert defines the resource implementation.
 type commonCert struct {
-	certType uint32
+	certType        uint32
+	revocationStore *revocationStore
 }
 
 // commonCertModel describes the resource data model.
 type commonCertModel struct {
-	CAPrivateKeyPEM     types.String `tfsdk:"ca_private_key_pem"`
-	PublicKeyOpenSSH    types.String `tfsdk:"public_key_openssh"`
-	ValidityPeriodHours types.Int64  `tfsdk:"validity_period_hours"`
-	KeyID               types.String `tfsdk:"key_id"`
-	ValidPrincipals     types.List   `tfsdk:"valid_principals"`
-	CriticalOptions     types.Map    `tfsdk:"critical_options"`
-	Extensions          types.Map    `tfsdk:"extensions"`
-	EarlyRenewalHours   types.Int64  `tfsdk:"early_renewal_hours"`
-	ReadyForRenewal     types.Bool   `tfsdk:"ready_for_renewal"`
-	ValidityStartTime   types.String `tfsdk:"validity_start_time"`
-	ValidityEndTime     types.String `tfsdk:"validity_end_time"`
-	CAKeyAlgorithm      types.String `tfsdk:"ca_key_algorithm"`
-	CertAuthorizedKey   types.String `tfsdk:"cert_authorized_key"`
-	ID                  types.String `tfsdk:"id"`
+	CAPrivateKeyPEM       privateKeyPEMValue    `tfsdk:"ca_private_key_pem"`
+	PublicKeyOpenSSH      publicKeyOpenSSHValue `tfsdk:"public_key_openssh"`
+	PublicKeyPEM          types.String          `tfsdk:"public_key_pem"`
+	PublicKeysOpenSSH     types.List            `tfsdk:"public_keys_openssh"`
+	ValidityPeriodHours   types.Int64           `tfsdk:"validity_period_hours"`
+	KeyID                 types.String          `tfsdk:"key_id"`
+	ValidPrincipals       types.List            `tfsdk:"valid_principals"`
+	CriticalOptions       types.Map             `tfsdk:"critical_options"`
+	Extensions            types.Map             `tfsdk:"extensions"`
+	EarlyRenewalHours     types.Int64           `tfsdk:"early_renewal_hours"`
+	EarlyRenewalFraction  types.Float64         `tfsdk:"early_renewal_fraction"`
+	EarlyRenewal          types.String          `tfsdk:"early_renewal"`
+	EarlyRenewalJitter    types.String          `tfsdk:"early_renewal_jitter"`
+	AlignExpiryTo         types.String          `tfsdk:"align_expiry_to"`
+	StagedRotationOverlap types.String          `tfsdk:"staged_rotation_overlap"`
+	Triggers              types.Map             `tfsdk:"triggers"`
+	Comment               types.String          `tfsdk:"comment"`
+	RevokeOnDestroy       types.Bool            `tfsdk:"revoke_on_destroy"`
+	ReadyForRenewal       types.Bool            `tfsdk:"ready_for_renewal"`
+	ValidityStartTime     types.String          `tfsdk:"validity_start_time"`
+	ValidityEndTime       types.String          `tfsdk:"validity_end_time"`
+	CAKeyAlgorithm        types.String          `tfsdk:"ca_key_algorithm"`
+	CAPublicKeyOpenSSH    types.String          `tfsdk:"ca_public_key_openssh"`
+	CAKeyFingerprint      types.String          `tfsdk:"ca_key_fingerprint_sha256"`
+	PublicKeyFingerprint  types.String          `tfsdk:"public_key_fingerprint_sha256"`
+	SubjectKeyAlgorithm   types.String          `tfsdk:"subject_key_algorithm"`
+	CertAuthorizedKey     types.String          `tfsdk:"cert_authorized_key"`
+	CertAuthorizedKeys    types.Map             `tfsdk:"cert_authorized_keys"`
+	NextCertAuthorizedKey types.String          `tfsdk:"next_cert_authorized_key"`
+	IssuanceCount         types.Int64           `tfsdk:"issuance_count"`
+	CertBase64            types.String          `tfsdk:"cert_base64"`
+	CertJSON              types.String          `tfsdk:"cert_json"`
+	CertText              types.String          `tfsdk:"cert_text"`
+	CertFingerprint       types.String          `tfsdk:"cert_fingerprint_sha256"`
+	ID                    types.String          `tfsdk:"id"`
 }
 
 func (r *commonCert) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
-		MarkdownDescription: "Create SSH certificate",
+		MarkdownDescription: "Create SSH certificate. It is re-issued in place when it is ready for renewal or `triggers` change",
 
 		Attributes: map[string]schema.Attribute{
 			"ca_private_key_pem": schema.StringAttribute{
-				Required: true,
+				CustomType: privateKeyPEMType{},
+				Required:   true,
 				PlanModifiers: []planmodifier.String{
-					requireReplaceIfStateContainsPEMString(),
+					requireReplaceIfPrivateKeyChanged(),
 				},
 				Sensitive: true,
 				Description: "Private key of the Certificate Authority (CA) used to sign the certificate, " +
-					"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
+					"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"Only a different key reissues the certificate, not a different encoding of the same key.",
 			},
 			"public_key_openssh": schema.StringAttribute{
-				Required: true,
+				CustomType: publicKeyOpenSSHType{},
+				Optional:   true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requireReplaceIfPublicKeyChanged(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("public_key_openssh"), path.MatchRoot("public_key_pem"), path.MatchRoot("public_keys_openssh")),
 				},
 				Description: "SSH public key to sign, " +
-					"in authorized keys format.",
+					"in authorized keys or [RFC 4716](https://datatracker.ietf.org/doc/html/rfc4716) " +
+					"(`---- BEGIN SSH2 PUBLIC KEY ----`) format. " +
+					"Exactly one of `public_key_openssh`, `public_key_pem` or `public_keys_openssh` must be set. " +
+					"Changing only the format or comment of the key does not reissue the certificate.",
+			},
+			"public_key_pem": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Description: "Public key to sign, " +
+					"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"Either a `PUBLIC KEY` (SubjectPublicKeyInfo), or an X.509 `CERTIFICATE` whose public key is signed, " +
+					"so that workloads with an existing TLS identity can be issued an SSH certificate for the same key.",
+			},
+			"public_keys_openssh": schema.ListAttribute{
+				ElementType: publicKeyOpenSSHType{},
+				Optional:    true,
+				PlanModifiers: []planmodifier.List{
+					requireReplaceIfPublicKeysChanged(),
+				},
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				Description: "List of SSH public keys to sign with the same key ID, principals, options, validity and serial number, " +
+					"in the same formats as `public_key_openssh`, e.g. the RSA, ECDSA and Ed25519 keys of one host. " +
+					"Each key must be of a different type. The certificates are output in `cert_authorized_keys`, " +
+					"and the other certificate attributes describe the certificate of the first key.",
 			},
 			"validity_period_hours": schema.Int64Attribute{
 				Required: true,
@@ -98,6 +158,9 @@ func (r *commonCert) Schema(ctx context.Context, req resource.SchemaRequest, res
 				PlanModifiers: []planmodifier.List{
 					listplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(principalValidator()),
+				},
 				Description: "List of hostnames to use as subjects of the certificate.",
 			},
 			"critical_options": schema.MapAttribute{
@@ -128,11 +191,49 @@ func (r *commonCert) Schema(ctx context.Context, req resource.SchemaRequest, res
 				Description: "The resource will consider the certificate to have expired the given number of hours " +
 					"before its actual expiry time. This can be useful to deploy an updated certificate in advance of " +
 					"the expiration of the current certificate. " +
-					"However, the old certificate remains valid until its true expiration time, since this resource " +
-					"does not (and cannot) support certificate revocation. " +
+					"However, the old certificate remains valid until its true expiration time, unless it is revoked " +
+					"with `revoke_on_destroy` or `ssh_krl`. " +
 					"Also, this advance update can only be performed should the Terraform configuration be applied " +
 					"during the early renewal period. (default: `0`)",
 			},
+			"early_renewal_fraction": earlyRenewalFractionAttribute(),
+			"early_renewal":          earlyRenewalAttribute(),
+			"early_renewal_jitter":   earlyRenewalJitterAttribute(),
+			"align_expiry_to":        alignExpiryToAttribute(stringplanmodifier.RequiresReplace()),
+			"staged_rotation_overlap": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(2),
+					stringvalidator.RegexMatches(earlyRenewalRegexp, "must be a duration in hours and minutes, e.g. `36h` or `1h30m`"),
+					stringvalidator.ConflictsWith(path.MatchRoot("public_keys_openssh")),
+				},
+				Description: "Rotate the certificate in two stages, with the given overlap in hours and minutes, e.g. `24h`. " +
+					"Once the certificate is ready for renewal, it is kept, and the next certificate is issued in " +
+					"`next_cert_authorized_key`, so that both can be distributed. The next certificate becomes current " +
+					"at the first apply at least the overlap after it was issued. An expired certificate is re-issued as usual. " +
+					"The overlap must be shorter than the early renewal period. Cannot be used with `public_keys_openssh`.",
+			},
+			"comment": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "Comment appended to `cert_authorized_key`. " +
+					"Defaults to the comment of `public_key_openssh`, or its `Comment` header in RFC 4716 format, if any. " +
+					"With `public_keys_openssh`, the comment of the first key is used for all certificates.",
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Arbitrary map of values that, when changed, re-issues the certificate in place, " +
+					"e.g. the ID of a rebuilt host or the version of a policy the certificate is issued under.",
+			},
+			"revoke_on_destroy": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "Record the certificate serial number, key ID and CA in the provider `revocation_store_path` " +
+					"when the resource is destroyed or replaced, or the certificate is re-issued or superseded by a staged rotation, " +
+					"so that it can be revoked with `ssh_revocation_store` and `ssh_krl`. The value in the state is used, so it must be applied before the destroy. (default: `false`)",
+			},
 			"ready_for_renewal": schema.BoolAttribute{
 				Computed: true,
 				Default:  booldefault.StaticBool(false),
@@ -140,7 +241,7 @@ func (r *commonCert) Schema(ctx context.Context, req resource.SchemaRequest, res
 					attribute_plan_modifier_bool.ReadyForRenewal(),
 				},
 				Description: "Is the certificate either expired (i.e. beyond the `validity_period_hours`) " +
-					"or ready for an early renewal (i.e. within the `early_renewal_hours`)?",
+					"or ready for an early renewal (i.e. within `early_renewal_hours`, `early_renewal_fraction` or `early_renewal`)?",
 			},
 			"validity_start_time": schema.StringAttribute{
 				Computed: true,
@@ -148,7 +249,8 @@ func (r *commonCert) Schema(ctx context.Context, req resource.SchemaRequest, res
 					stringplanmodifier.UseStateForUnknown(),
 				},
 				Description: "The time after which the certificate is valid, " +
-					"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+					"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp. " +
+					"Known after apply whenever the certificate is issued, see `id`.",
 			},
 			"validity_end_time": schema.StringAttribute{
 				Computed: true,
@@ -156,7 +258,8 @@ func (r *commonCert) Schema(ctx context.Context, req resource.SchemaRequest, res
 					stringplanmodifier.UseStateForUnknown(),
 				},
 				Description: "The time until which the certificate is invalid, " +
-					"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+					"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp. " +
+					"Known after apply whenever the certificate is issued, see `id`.",
 			},
 			"ca_key_algorithm": schema.StringAttribute{
 				Computed: true,
@@ -165,25 +268,126 @@ func (r *commonCert) Schema(ctx context.Context, req resource.SchemaRequest, res
 				},
 				Description: "Name of the algorithm used when generating the private key provided in `ca_private_key_pem`. ",
 			},
+			"ca_public_key_openssh": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Public key of the Certificate Authority (CA), in authorized keys format. " +
+					"Suitable for use in `TrustedUserCAKeys` or `@cert-authority` entries.",
+			},
+			"ca_key_fingerprint_sha256": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "SHA256 fingerprint of the Certificate Authority (CA) public key, " +
+					"in the format printed by `ssh-keygen -l`.",
+			},
+			"public_key_fingerprint_sha256": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "SHA256 fingerprint of the signed public key, " +
+					"in the format printed by `ssh-keygen -l`.",
+			},
+			"subject_key_algorithm": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Name of the algorithm of the public key provided in `public_key_openssh` or `public_key_pem`, " +
+					"or of the first key in `public_keys_openssh`.",
+			},
 			"cert_authorized_key": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
-				Description: "Signed SSH certificate.",
+				Description: "Signed SSH certificate, in authorized keys format.",
+			},
+			"next_cert_authorized_key": schema.StringAttribute{
+				Computed: true,
+				Description: "Next SSH certificate, in authorized keys format, issued by a staged rotation " +
+					"(see `staged_rotation_overlap`) ahead of replacing `cert_authorized_key`. " +
+					"Null if no rotation is in progress.",
+			},
+			"cert_authorized_keys": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Map of signed SSH certificates in authorized keys format, " +
+					"keyed by the type of the signed public key (e.g. `ssh-ed25519`).",
+			},
+			"cert_base64": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Signed SSH certificate, in base64 encoded SSH wire format.",
+			},
+			"cert_json": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "JSON description of the signed SSH certificate.",
+			},
+			"cert_text": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Human-readable description of the signed SSH certificate, " +
+					"in the format printed by `ssh-keygen -L`.",
+			},
+			"cert_fingerprint_sha256": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "SHA256 fingerprint of the signed SSH certificate.",
+			},
+			"issuance_count": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Description: "Number of certificates issued by this resource: 1 when it is created, incremented " +
+					"whenever the certificate is re-issued in place, as it is ready for renewal or `triggers` changed, " +
+					"and for each next certificate of a staged rotation (see `staged_rotation_overlap`). " +
+					"Replacing the resource, e.g. as the key ID or principals changed, counts from 1 again.",
 			},
 			"id": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
-				Description: "Unique identifier for this resource: the certificate serial number.",
+				Description: "Unique identifier for this resource: the certificate serial number. " +
+					"The serial number and validity times of a certificate are chosen when it is issued, during apply, " +
+					"so they are not known at plan time. Terraform plans the resource again during apply and requires " +
+					"known planned values to stay the same, which a random serial number and the current time cannot.",
 			},
 		},
 	}
 }
 
 func (r *commonCert) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	store, ok := req.ProviderData.(*revocationStore)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *revocationStore, got: %T. Please report this issue to the provider developers.", req.ProviderData))
+		return
+	}
+	r.revocationStore = store
 }
 
 func (r *commonCert) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -192,73 +396,589 @@ func (r *commonCert) Create(ctx context.Context, req resource.CreateRequest, res
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	newState.NextCertAuthorizedKey = types.StringNull()
 
-	certificate, diags := baseCertificate(ctx, &req.Plan)
-	if diags.HasError() {
-		resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(r.issueCertificates(ctx, &req.Plan, &newState)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+// issueCertificates signs the certificates of all subject public keys in the model, and sets the
+// certificate attributes of the model.
+func (r *commonCert) issueCertificates(ctx context.Context, plan *tfsdk.Plan, model *commonCertModel) diag.Diagnostics {
+	certificate, diags := baseCertificate(ctx, plan)
+	if diags.HasError() {
+		return diags
+	}
 	certificate.CertType = r.certType
 
-	caPrvKey, algorithm, err := parsePrivateKeyPEM([]byte(newState.CAPrivateKeyPEM.ValueString()))
+	caPrvKey, _, err := parsePrivateKeyPEM([]byte(model.CAPrivateKeyPEM.ValueString()))
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to parse CA private key PEM", err.Error())
-		return
+		diags.AddError("Failed to parse CA private key PEM", err.Error())
+		return diags
 	}
 	signer, err := ssh.NewSignerFromKey(caPrvKey)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to create signer with private key", err.Error())
+		diags.AddError("Failed to create signer with private key", err.Error())
+		return diags
+	}
+
+	pubKeys, comment, d := subjectPublicKeys(ctx, model)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+	if model.Comment.IsUnknown() {
+		model.Comment = types.StringValue(comment)
+	}
+	if model.ReadyForRenewal.IsUnknown() {
+		model.ReadyForRenewal = types.BoolValue(false)
+	}
+
+	// All certificates share the template, so that they are renewed and revoked together
+	certificates := make([]*ssh.Certificate, 0, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		c := *certificate
+		c.Key = pubKey
+		if err := c.SignCert(rand.Reader, signer); err != nil {
+			diags.AddError("Failed sign cert", err.Error())
+			return diags
+		}
+		certificates = append(certificates, &c)
+	}
+
+	diags.Append(updateModelFromCertificate(certificates[0], model)...)
+	if diags.HasError() {
+		return diags
+	}
+	model.CertAuthorizedKeys, d = certificatesToAuthorizedKeysMap(certificates, model.Comment.ValueString())
+	diags.Append(d...)
+	return diags
+}
+
+func (r *commonCert) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	modifyStateIfCertificateReadyForRenewal(ctx, req, resp, path.Root("id"))
+}
+
+// Update re-issues the certificate or issues the next certificate of a staged rotation, if planned,
+// and otherwise stores the plan. A certificate that is re-issued or superseded by the next one is
+// recorded in the revocation store if revoke_on_destroy is set, as when it is destroyed.
+func (r *commonCert) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var newState, state commonCertModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &newState)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	newState.CAKeyAlgorithm = types.StringValue(algorithm.String())
 
-	pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(newState.PublicKeyOpenSSH.ValueString()))
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to marshal public key error", err.Error())
+	if newState.ID.IsUnknown() {
+		resp.Diagnostics.Append(r.issueCertificates(ctx, &req.Plan, &newState)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if newState.NextCertAuthorizedKey.IsUnknown() {
+		certificate, diags := r.signNextCertificate(ctx, &req.Plan, &newState)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		newState.NextCertAuthorizedKey = types.StringValue(marshalCertificate(certificate, newState.Comment.ValueString()))
+	}
+
+	if state.RevokeOnDestroy.ValueBool() && !newState.ID.Equal(state.ID) {
+		resp.Diagnostics.Append(recordRevokedCertificate(r.revocationStore, revocationRecord{
+			Serial:             state.ID.ValueString(),
+			KeyID:              state.KeyID.ValueString(),
+			CAPublicKeyOpenSSH: state.CAPublicKeyOpenSSH.ValueString(),
+			CAKeyFingerprint:   state.CAKeyFingerprint.ValueString(),
+		})...)
+	}
+	// The next certificate of a staged rotation is discarded if it was not promoted
+	if state.RevokeOnDestroy.ValueBool() && !state.NextCertAuthorizedKey.IsNull() && newState.NextCertAuthorizedKey.IsNull() {
+		resp.Diagnostics.Append(r.recordRevokedNextCertificate(&state, newState.ID.ValueString())...)
+	}
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	certificate.Key = pubKey
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
 
+// signNextCertificate issues the next certificate of a staged rotation, with the same options as
+// the current certificate, but a new serial number and a validity period starting now.
+func (r *commonCert) signNextCertificate(ctx context.Context, plan *tfsdk.Plan, model *commonCertModel) (*ssh.Certificate, diag.Diagnostics) {
+	certificate, diags := baseCertificate(ctx, plan)
+	if diags.HasError() {
+		return nil, diags
+	}
+	certificate.CertType = r.certType
+
+	signer, d := parseCASigner(model.CAPrivateKeyPEM.ValueString())
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	pubKeys, _, d := subjectPublicKeys(ctx, model)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	certificate.Key = pubKeys[0]
 	if err := certificate.SignCert(rand.Reader, signer); err != nil {
-		resp.Diagnostics.AddError("Failed sign cert", err.Error())
-		return
+		diags.AddError("Failed sign cert", err.Error())
+		return nil, diags
 	}
+	return certificate, diags
+}
 
-	validFromBytes, err := time.Unix(int64(certificate.ValidAfter), 0).MarshalText()
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to serialize validity start time", err.Error())
+// Delete records the certificate in the revocation store if revoke_on_destroy is set.
+// Otherwise, the certificate is only removed from the state and remains valid until it expires.
+func (r *commonCert) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state commonCertModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	validToBytes, err := time.Unix(int64(certificate.ValidBefore), 0).MarshalText()
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to serialize validity end time", err.Error())
+	if !state.RevokeOnDestroy.ValueBool() {
 		return
 	}
 
-	newState.ID = types.StringValue(fmt.Sprintf("%d", certificate.Serial))
-	newState.CertAuthorizedKey = types.StringValue(string(ssh.MarshalAuthorizedKey(certificate)))
-	newState.ValidityStartTime = types.StringValue(string(validFromBytes))
-	newState.ValidityEndTime = types.StringValue(string(validToBytes))
-	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+	resp.Diagnostics.Append(recordRevokedCertificate(r.revocationStore, revocationRecord{
+		Serial:             state.ID.ValueString(),
+		KeyID:              state.KeyID.ValueString(),
+		CAPublicKeyOpenSSH: state.CAPublicKeyOpenSSH.ValueString(),
+		CAKeyFingerprint:   state.CAKeyFingerprint.ValueString(),
+	})...)
+
+	// The next certificate of a staged rotation is just as valid
+	if state.NextCertAuthorizedKey.IsNull() || resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.recordRevokedNextCertificate(&state, "")...)
 }
 
-func (r *commonCert) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	modifyStateIfCertificateReadyForRenewal(ctx, req, resp)
+// recordRevokedNextCertificate records the next certificate of a staged rotation in the state in
+// the revocation store, unless it has the serial number of the certificate that is kept.
+func (r *commonCert) recordRevokedNextCertificate(state *commonCertModel, keptSerial string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	nextCertificate, err := parseCertificate(state.NextCertAuthorizedKey.ValueString())
+	if err != nil {
+		diags.AddError("Failed to parse next certificate from state", err.Error())
+		return diags
+	}
+	serial := fmt.Sprintf("%d", nextCertificate.Serial)
+	if serial == keptSerial {
+		return diags
+	}
+	return recordRevokedCertificate(r.revocationStore, revocationRecord{
+		Serial:             serial,
+		KeyID:              nextCertificate.KeyId,
+		CAPublicKeyOpenSSH: state.CAPublicKeyOpenSSH.ValueString(),
+		CAKeyFingerprint:   state.CAKeyFingerprint.ValueString(),
+	})
 }
 
-func (r *commonCert) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	updatedUsingPlan(ctx, &req, resp, &commonCertModel{})
+// recordRevokedCertificate appends the certificate to the revocation store, as revoked now.
+func recordRevokedCertificate(store *revocationStore, record revocationRecord) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if store == nil {
+		diags.AddAttributeError(path.Root("revoke_on_destroy"), "Revocation store not configured",
+			"Set the provider revocation_store_path to revoke certificates on destroy.")
+		return diags
+	}
+	if record.CAPublicKeyOpenSSH == "" {
+		diags.AddAttributeError(path.Root("revoke_on_destroy"), "Failed to revoke certificate",
+			"The CA public key of the certificate is not known. Import the certificate in authorized keys format, "+
+				"or unset revoke_on_destroy.")
+		return diags
+	}
+
+	revokedTimeBytes, err := overridableTimeFunc().MarshalText()
+	if err != nil {
+		diags.AddError("Failed to serialize revoked time", err.Error())
+		return diags
+	}
+	record.RevokedTime = string(revokedTimeBytes)
+	if err := store.Append(record); err != nil {
+		diags.AddError("Failed to record revoked certificate", err.Error())
+	}
+	return diags
 }
 
-func (r *commonCert) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+// validateRevokeOnDestroy checks at plan time that the certificates which revoke_on_destroy records
+// can be recorded, so that an apply or destroy does not fail in Update or Delete instead. The state
+// value applies to certificates retired by this plan, and the planned value to later ones.
+func validateRevokeOnDestroy(ctx context.Context, state tfsdk.State, plan tfsdk.Plan, store *revocationStore) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var stateRevoke, planRevoke types.Bool
+	var stateID, planID, stateCAKey, planCAKey types.String
+	if !state.Raw.IsNull() {
+		diags.Append(state.GetAttribute(ctx, path.Root("revoke_on_destroy"), &stateRevoke)...)
+		diags.Append(state.GetAttribute(ctx, path.Root("id"), &stateID)...)
+		diags.Append(state.GetAttribute(ctx, path.Root("ca_public_key_openssh"), &stateCAKey)...)
+	}
+	if !plan.Raw.IsNull() {
+		diags.Append(plan.GetAttribute(ctx, path.Root("revoke_on_destroy"), &planRevoke)...)
+		diags.Append(plan.GetAttribute(ctx, path.Root("id"), &planID)...)
+		diags.Append(plan.GetAttribute(ctx, path.Root("ca_public_key_openssh"), &planCAKey)...)
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	retiresCertificate := stateRevoke.ValueBool() && (plan.Raw.IsNull() || !planID.Equal(stateID))
+	if !retiresCertificate && !planRevoke.ValueBool() {
+		return diags
+	}
+	if store == nil {
+		diags.AddAttributeError(path.Root("revoke_on_destroy"), "Revocation store not configured",
+			"Set the provider revocation_store_path to revoke certificates on destroy, or unset revoke_on_destroy.")
+		return diags
+	}
+	if (retiresCertificate && stateCAKey.ValueString() == "") ||
+		(planRevoke.ValueBool() && !planCAKey.IsUnknown() && planCAKey.ValueString() == "") {
+		diags.AddAttributeError(path.Root("revoke_on_destroy"), "Cannot revoke certificate",
+			"The CA public key of the certificate is not known. Import the certificate in authorized keys format, "+
+				"or unset revoke_on_destroy.")
+	}
+	return diags
 }
 
+// ImportState accepts either the certificate serial number, or the certificate itself
+// in authorized keys format. When given the certificate, all attributes that can be
+// derived from it are populated.
 func (r *commonCert) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	pubKey, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(req.ID))
+	if err != nil {
+		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+		return
+	}
+	certificate, ok := pubKey.(*ssh.Certificate)
+	if !ok {
+		resp.Diagnostics.AddError("Failed to import certificate", fmt.Sprintf("expected an SSH certificate, got key of type %s", pubKey.Type()))
+		return
+	}
+	if certificate.CertType != r.certType {
+		resp.Diagnostics.AddError("Failed to import certificate", fmt.Sprintf("unexpected certificate type: %d", certificate.CertType))
+		return
+	}
+	// validity_period_hours and the validity times can only describe a bounded window
+	if certificate.ValidAfter == 0 || certificate.ValidBefore == ssh.CertTimeInfinity ||
+		certificate.ValidBefore > math.MaxInt64 || certificate.ValidBefore <= certificate.ValidAfter {
+		resp.Diagnostics.AddError("Failed to import certificate",
+			fmt.Sprintf("certificate validity %s is not a bounded time window; only certificates with a start and an expiry time can be imported", certificateValidityText(certificate)))
+		return
+	}
+
+	var diags diag.Diagnostics
+	state := commonCertModel{
+		CAPrivateKeyPEM:       newPrivateKeyPEMNull(),
+		PublicKeyOpenSSH:      newPublicKeyOpenSSHValue(string(ssh.MarshalAuthorizedKey(certificate.Key))),
+		PublicKeyPEM:          types.StringNull(),
+		PublicKeysOpenSSH:     publicKeysOpenSSHNull(),
+		ValidityPeriodHours:   types.Int64Value(int64(certificate.ValidBefore-certificate.ValidAfter) / 3600),
+		KeyID:                 types.StringValue(certificate.KeyId),
+		EarlyRenewalHours:     types.Int64Value(0),
+		EarlyRenewalFraction:  types.Float64Null(),
+		EarlyRenewal:          types.StringNull(),
+		EarlyRenewalJitter:    types.StringNull(),
+		AlignExpiryTo:         types.StringNull(),
+		StagedRotationOverlap: types.StringNull(),
+		Triggers:              types.MapNull(types.StringType),
+		NextCertAuthorizedKey: types.StringNull(),
+		IssuanceCount:         types.Int64Value(1),
+		ReadyForRenewal:       types.BoolValue(false),
+		RevokeOnDestroy:       types.BoolValue(false),
+		Comment:               types.StringValue(comment),
+	}
+	state.ValidPrincipals, diags = types.ListValueFrom(ctx, types.StringType, certificate.ValidPrincipals)
+	resp.Diagnostics.Append(diags...)
+	state.CriticalOptions, diags = types.MapValueFrom(ctx, types.StringType, certificate.CriticalOptions)
+	resp.Diagnostics.Append(diags...)
+	state.Extensions, diags = types.MapValueFrom(ctx, types.StringType, certificate.Extensions)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(updateModelFromCertificate(certificate, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.CertAuthorizedKeys, diags = certificatesToAuthorizedKeysMap([]*ssh.Certificate{certificate}, comment)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
 func (r *commonCert) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, res *resource.ModifyPlanResponse) {
-	modifyPlanIfCertificateReadyForRenewal(ctx, &req, res)
+	if !req.Plan.Raw.IsNull() {
+		res.Diagnostics.Append(validateCertificateSchedule(ctx, req.Plan)...)
+	}
+	staged, diags := stagedRotationApplies(ctx, &req)
+	res.Diagnostics.Append(diags...)
+	if res.Diagnostics.HasError() {
+		return
+	}
+	modifyPlanForCertificateComment(ctx, &req, res)
+	if res.Diagnostics.HasError() {
+		return
+	}
+	if staged {
+		modifyPlanForStagedRotation(ctx, &req, res)
+	} else if !req.Plan.Raw.IsNull() {
+		res.Diagnostics.Append(res.Plan.SetAttribute(ctx, path.Root("next_cert_authorized_key"), types.StringNull())...)
+		modifyPlanForReissue(ctx, &req, res)
+	}
+	if !req.Plan.Raw.IsNull() && req.State.Raw.IsNull() {
+		res.Diagnostics.Append(res.Plan.SetAttribute(ctx, path.Root("issuance_count"), types.Int64Value(1))...)
+	}
+	if res.Diagnostics.HasError() {
+		return
+	}
+	res.Diagnostics.Append(validateRevokeOnDestroy(ctx, req.State, res.Plan, r.revocationStore)...)
+}
+
+// stagedRotationApplies reports whether the existing certificate is rotated in stages, as
+// `staged_rotation_overlap` is set, the certificate has not expired yet and `triggers` did not change.
+// Otherwise, the certificate is re-issued as usual. It also checks that the overlap is shorter than
+// the early renewal period, as the certificate would otherwise expire before the next one is used.
+func stagedRotationApplies(ctx context.Context, req *resource.ModifyPlanRequest) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return false, diags
+	}
+
+	var overlapStr, validityStartTimeStr, validityEndTimeStr types.String
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("staged_rotation_overlap"), &overlapStr)...)
+	diags.Append(req.State.GetAttribute(ctx, path.Root("validity_start_time"), &validityStartTimeStr)...)
+	diags.Append(req.State.GetAttribute(ctx, path.Root("validity_end_time"), &validityEndTimeStr)...)
+	if diags.HasError() || overlapStr.IsNull() || overlapStr.IsUnknown() {
+		return false, diags
+	}
+	overlap, err := time.ParseDuration(overlapStr.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("staged_rotation_overlap"), "Failed to parse staged rotation overlap", err.Error())
+		return false, diags
+	}
+	validityStartTime, err := time.Parse(time.RFC3339, validityStartTimeStr.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	validityEndTime, err := time.Parse(time.RFC3339, validityEndTimeStr.ValueString())
+	if err != nil {
+		return false, diags
+	}
+
+	schedule, known, d := getCertificateSchedule(ctx, req.Plan)
+	diags.Append(d...)
+	if diags.HasError() || !known {
+		return false, diags
+	}
+	earlyRenewalPeriod, err := schedule.earlyRenewalPeriod(validityEndTime.Sub(validityStartTime))
+	if err != nil {
+		diags.AddAttributeError(path.Root("early_renewal"), "Failed to parse early renewal period", err.Error())
+		return false, diags
+	}
+	if overlap >= earlyRenewalPeriod {
+		diags.AddAttributeError(path.Root("staged_rotation_overlap"), "Invalid staged rotation overlap",
+			fmt.Sprintf("The overlap of %s must be shorter than the early renewal period of %s.", overlap, earlyRenewalPeriod))
+		return false, diags
+	}
+	if !overridableTimeFunc().Before(validityEndTime) {
+		return false, diags
+	}
+	triggersChanged, d := certificateTriggersChanged(ctx, req)
+	diags.Append(d...)
+	return !triggersChanged, diags
+}
+
+// certificateTriggersChanged reports whether `triggers` differ between the plan and the state.
+func certificateTriggersChanged(ctx context.Context, req *resource.ModifyPlanRequest) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var planTriggers, stateTriggers types.Map
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("triggers"), &planTriggers)...)
+	diags.Append(req.State.GetAttribute(ctx, path.Root("triggers"), &stateTriggers)...)
+	return !planTriggers.Equal(stateTriggers), diags
+}
+
+// modifyPlanForReissue plans to re-issue the certificate in place, with a new serial number and
+// validity period, once it is ready for renewal or `triggers` changed. Unlike a replacement of the
+// resource, this keeps counting the certificates issued in `issuance_count`.
+func modifyPlanForReissue(ctx context.Context, req *resource.ModifyPlanRequest, res *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var plan, state commonCertModel
+	res.Diagnostics.Append(res.Plan.Get(ctx, &plan)...)
+	res.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if res.Diagnostics.HasError() {
+		return
+	}
+
+	reissue, diags := certificateTriggersChanged(ctx, req)
+	res.Diagnostics.Append(diags...)
+	if res.Diagnostics.HasError() {
+		return
+	}
+	if reissue {
+		tflog.Info(ctx, "Certificate triggers changed, re-issuing the certificate")
+	} else {
+		earlyRenewalTime, known, diags := getCertificateRenewalTime(ctx, req.Plan, path.Root("id"))
+		res.Diagnostics.Append(diags...)
+		if res.Diagnostics.HasError() || !known || overridableTimeFunc().Before(earlyRenewalTime) {
+			return
+		}
+		tflog.Info(ctx, "Certificate is ready for early renewal, re-issuing the certificate")
+	}
+
+	plan.ID = types.StringUnknown()
+	plan.ValidityStartTime = types.StringUnknown()
+	plan.ValidityEndTime = types.StringUnknown()
+	plan.CertAuthorizedKey = types.StringUnknown()
+	plan.CertAuthorizedKeys = types.MapUnknown(types.StringType)
+	plan.CertBase64 = types.StringUnknown()
+	plan.CertJSON = types.StringUnknown()
+	plan.CertText = types.StringUnknown()
+	plan.CertFingerprint = types.StringUnknown()
+	plan.ReadyForRenewal = types.BoolUnknown()
+	plan.IssuanceCount = types.Int64Value(state.IssuanceCount.ValueInt64() + 1)
+	res.Diagnostics.Append(res.Plan.Set(ctx, plan)...)
+}
+
+// modifyPlanForStagedRotation plans the next certificate to be issued in `next_cert_authorized_key`
+// once the certificate is ready for renewal, and keeps the current certificate until the next one
+// has been issued for at least `staged_rotation_overlap`. The next certificate then becomes current.
+func modifyPlanForStagedRotation(ctx context.Context, req *resource.ModifyPlanRequest, res *resource.ModifyPlanResponse) {
+	var plan, state commonCertModel
+	res.Diagnostics.Append(res.Plan.Get(ctx, &plan)...)
+	res.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if res.Diagnostics.HasError() {
+		return
+	}
+	overlap, err := time.ParseDuration(plan.StagedRotationOverlap.ValueString())
+	if err != nil {
+		res.Diagnostics.AddAttributeError(path.Root("staged_rotation_overlap"), "Failed to parse staged rotation overlap", err.Error())
+		return
+	}
+	currentTime := overridableTimeFunc()
+
+	// Issue the next certificate once the current one is ready for renewal
+	if state.NextCertAuthorizedKey.IsNull() || state.NextCertAuthorizedKey.IsUnknown() {
+		earlyRenewalTime, known, diags := getCertificateRenewalTime(ctx, req.Plan, path.Root("id"))
+		res.Diagnostics.Append(diags...)
+		if res.Diagnostics.HasError() {
+			return
+		}
+		plan.NextCertAuthorizedKey = types.StringNull()
+		if !known || currentTime.Before(earlyRenewalTime) {
+			res.Diagnostics.Append(res.Plan.Set(ctx, plan)...)
+			return
+		}
+		tflog.Info(ctx, "Certificate is ready for early renewal, issuing the next certificate")
+		plan.NextCertAuthorizedKey = types.StringUnknown()
+		plan.ReadyForRenewal = types.BoolValue(true)
+		plan.IssuanceCount = types.Int64Value(state.IssuanceCount.ValueInt64() + 1)
+		res.Diagnostics.Append(res.Plan.Set(ctx, plan)...)
+		return
+	}
+
+	nextCertificate, err := parseCertificate(state.NextCertAuthorizedKey.ValueString())
+	if err != nil {
+		res.Diagnostics.AddError("Failed to parse next certificate from state", err.Error())
+		return
+	}
+
+	// Keep both certificates until the overlap has passed
+	if currentTime.Before(time.Unix(int64(nextCertificate.ValidAfter), 0).Add(overlap)) {
+		plan.NextCertAuthorizedKey = types.StringValue(marshalCertificate(nextCertificate, plan.Comment.ValueString()))
+		plan.ReadyForRenewal = types.BoolValue(true)
+		res.Diagnostics.Append(res.Plan.Set(ctx, plan)...)
+		return
+	}
+
+	tflog.Info(ctx, "Staged rotation overlap has passed, the next certificate becomes current")
+	res.Diagnostics.Append(updateModelFromCertificate(nextCertificate, &plan)...)
+	if res.Diagnostics.HasError() {
+		return
+	}
+	var diags diag.Diagnostics
+	plan.CertAuthorizedKeys, diags = certificatesToAuthorizedKeysMap([]*ssh.Certificate{nextCertificate}, plan.Comment.ValueString())
+	res.Diagnostics.Append(diags...)
+	plan.NextCertAuthorizedKey = types.StringNull()
+	plan.ReadyForRenewal = types.BoolValue(false)
+	res.Diagnostics.Append(res.Plan.Set(ctx, plan)...)
+}
+
+// subjectPublicKeys returns the public keys to sign and the comment of the first key, from either
+// `public_key_openssh`, `public_key_pem` or `public_keys_openssh`. Keys in PEM format have no comment.
+func subjectPublicKeys(ctx context.Context, model *commonCertModel) ([]ssh.PublicKey, string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if !model.PublicKeyPEM.IsNull() {
+		pubKey, err := parsePublicKeyPEM([]byte(model.PublicKeyPEM.ValueString()))
+		if err != nil {
+			diags.AddAttributeError(path.Root("public_key_pem"), "Failed to parse public key PEM", err.Error())
+			return nil, "", diags
+		}
+		return []ssh.PublicKey{pubKey}, "", diags
+	}
+
+	if !model.PublicKeyOpenSSH.IsNull() {
+		pubKey, comment, err := parsePublicKeyOpenSSH(model.PublicKeyOpenSSH.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("public_key_openssh"), "Failed to parse public key", err.Error())
+			return nil, "", diags
+		}
+		return []ssh.PublicKey{pubKey}, comment, diags
+	}
+
+	var keys []string
+	diags.Append(model.PublicKeysOpenSSH.ElementsAs(ctx, &keys, false)...)
+	if diags.HasError() {
+		return nil, "", diags
+	}
+	var pubKeys []ssh.PublicKey
+	var firstComment string
+	keyTypes := make(map[string]struct{})
+	for i, key := range keys {
+		keyPath := path.Root("public_keys_openssh").AtListIndex(i)
+		pubKey, comment, err := parsePublicKeyOpenSSH(key)
+		if err != nil {
+			diags.AddAttributeError(keyPath, "Failed to parse public key", err.Error())
+			return nil, "", diags
+		}
+		if _, ok := keyTypes[pubKey.Type()]; ok {
+			diags.AddAttributeError(keyPath, "Duplicate public key type",
+				fmt.Sprintf("more than one public key of type %s", pubKey.Type()))
+			return nil, "", diags
+		}
+		keyTypes[pubKey.Type()] = struct{}{}
+		if i == 0 {
+			firstComment = comment
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	return pubKeys, firstComment, diags
+}
+
+// certificatesToAuthorizedKeysMap returns the certificates in authorized keys format,
+// keyed by the type of the signed public key.
+func certificatesToAuthorizedKeysMap(certificates []*ssh.Certificate, comment string) (types.Map, diag.Diagnostics) {
+	elements := make(map[string]attr.Value, len(certificates))
+	for _, certificate := range certificates {
+		elements[certificate.Key.Type()] = types.StringValue(marshalCertificate(certificate, comment))
+	}
+	return types.MapValue(types.StringType, elements)
+}
+
+// randomSerial returns a random certificate serial number.
+func randomSerial() (uint64, error) {
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serial, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return 0, err
+	}
+	return serial.Uint64(), nil
 }
 
 func baseCertificate(ctx context.Context, plan *tfsdk.Plan) (*ssh.Certificate, diag.Diagnostics) {
@@ -277,22 +997,22 @@ func baseCertificate(ctx context.Context, plan *tfsdk.Plan) (*ssh.Certificate, d
 	}
 	template.KeyId = keyID
 
-	var validityPeriodHours int64
-	diags.Append(plan.GetAttribute(ctx, path.Root("validity_period_hours"), &validityPeriodHours)...)
+	schedule, _, d := getCertificateSchedule(ctx, plan)
+	diags.Append(d...)
 	if diags.HasError() {
 		return nil, diags
 	}
-	now := overridableTimeFunc()
-	template.ValidAfter = uint64(now.Unix())
-	template.ValidBefore = uint64(now.Add(time.Duration(validityPeriodHours) * time.Hour).Unix())
+	validAfter := time.Unix(overridableTimeFunc().Unix(), 0)
+	validBefore := schedule.validityEnd(validAfter)
 
-	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
-	serial, err := rand.Int(rand.Reader, serialNumberLimit)
+	serial, err := randomSerial()
 	if err != nil {
 		diags.AddError("Failed to generate serial number", err.Error())
 		return nil, diags
 	}
-	template.Serial = serial.Uint64()
+	template.ValidAfter = uint64(validAfter.Unix())
+	template.ValidBefore = uint64(validBefore.Unix())
+	template.Serial = serial
 
 	var validPrincipals types.List
 	diags.Append(plan.GetAttribute(ctx, path.Root("valid_principals"), &validPrincipals)...)
@@ -335,3 +1055,50 @@ func baseCertificate(ctx context.Context, plan *tfsdk.Plan) (*ssh.Certificate, d
 
 	return template, nil
 }
+
+// updateModelFromCertificate sets all computed attributes that are derived from the signed certificate.
+func updateModelFromCertificate(certificate *ssh.Certificate, model *commonCertModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	validFromBytes, err := time.Unix(int64(certificate.ValidAfter), 0).MarshalText()
+	if err != nil {
+		diags.AddError("Failed to serialize validity start time", err.Error())
+		return diags
+	}
+	validToBytes, err := time.Unix(int64(certificate.ValidBefore), 0).MarshalText()
+	if err != nil {
+		diags.AddError("Failed to serialize validity end time", err.Error())
+		return diags
+	}
+
+	caAlgorithm, err := publicKeyToAlgorithm(certificate.SignatureKey)
+	if err != nil {
+		diags.AddError("Failed to determine CA key algorithm", err.Error())
+		return diags
+	}
+	subjectAlgorithm, err := publicKeyToAlgorithm(certificate.Key)
+	if err != nil {
+		diags.AddError("Failed to determine public key algorithm", err.Error())
+		return diags
+	}
+	certJSON, err := marshalCertificateJSON(certificate)
+	if err != nil {
+		diags.AddError("Failed to serialize certificate to JSON", err.Error())
+		return diags
+	}
+
+	model.ID = types.StringValue(fmt.Sprintf("%d", certificate.Serial))
+	model.CertAuthorizedKey = types.StringValue(marshalCertificate(certificate, model.Comment.ValueString()))
+	model.CertBase64 = types.StringValue(marshalCertificateBase64(certificate))
+	model.CertJSON = types.StringValue(certJSON)
+	model.CertText = types.StringValue(certificateText(certificate))
+	model.CertFingerprint = types.StringValue(ssh.FingerprintSHA256(certificate))
+	model.ValidityStartTime = types.StringValue(string(validFromBytes))
+	model.ValidityEndTime = types.StringValue(string(validToBytes))
+	model.CAKeyAlgorithm = types.StringValue(caAlgorithm.String())
+	model.CAPublicKeyOpenSSH = types.StringValue(string(ssh.MarshalAuthorizedKey(certificate.SignatureKey)))
+	model.CAKeyFingerprint = types.StringValue(ssh.FingerprintSHA256(certificate.SignatureKey))
+	model.PublicKeyFingerprint = types.StringValue(ssh.FingerprintSHA256(certificate.Key))
+	model.SubjectKeyAlgorithm = types.StringValue(subjectAlgorithm.String())
+	return diags
+}
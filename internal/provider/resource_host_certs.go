@@ -0,0 +1,507 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &hostCertsResource{}
+var _ resource.ResourceWithModifyPlan = &hostCertsResource{}
+
+func NewHostCertsResource() resource.Resource {
+	return &hostCertsResource{}
+}
+
+// hostCertsCertAttrTypes are the attribute types of hostCertsCertModel.
+var hostCertsCertAttrTypes = map[string]attr.Type{
+	"cert_authorized_key":           types.StringType,
+	"serial":                        types.StringType,
+	"validity_start_time":           types.StringType,
+	"validity_end_time":             types.StringType,
+	"public_key_fingerprint_sha256": types.StringType,
+}
+
+// hostCertsResource defines the resource implementation.
+type hostCertsResource struct{}
+
+// hostCertsResourceModel describes the resource data model.
+type hostCertsResourceModel struct {
+	CAPrivateKeyPEM      privateKeyPEMValue `tfsdk:"ca_private_key_pem"`
+	ValidityPeriodHours  types.Int64        `tfsdk:"validity_period_hours"`
+	EarlyRenewalHours    types.Int64        `tfsdk:"early_renewal_hours"`
+	EarlyRenewalFraction types.Float64      `tfsdk:"early_renewal_fraction"`
+	EarlyRenewal         types.String       `tfsdk:"early_renewal"`
+	EarlyRenewalJitter   types.String       `tfsdk:"early_renewal_jitter"`
+	AlignExpiryTo        types.String       `tfsdk:"align_expiry_to"`
+	Hosts                types.Map          `tfsdk:"hosts"`
+	Certs                types.Map          `tfsdk:"certs"`
+	CAPublicKeyOpenSSH   types.String       `tfsdk:"ca_public_key_openssh"`
+	ID                   types.String       `tfsdk:"id"`
+}
+
+// hostCertsHostModel describes a host to issue a certificate for.
+type hostCertsHostModel struct {
+	PublicKeyOpenSSH types.String `tfsdk:"public_key_openssh"`
+	ValidPrincipals  types.List   `tfsdk:"valid_principals"`
+	KeyID            types.String `tfsdk:"key_id"`
+}
+
+// hostCertsCertModel describes the certificate issued for a host.
+type hostCertsCertModel struct {
+	CertAuthorizedKey    types.String `tfsdk:"cert_authorized_key"`
+	Serial               types.String `tfsdk:"serial"`
+	ValidityStartTime    types.String `tfsdk:"validity_start_time"`
+	ValidityEndTime      types.String `tfsdk:"validity_end_time"`
+	PublicKeyFingerprint types.String `tfsdk:"public_key_fingerprint_sha256"`
+}
+
+// hostCertsSpec is the certificate requested for a host, with defaults applied.
+type hostCertsSpec struct {
+	pubKey     ssh.PublicKey
+	comment    string
+	keyID      string
+	principals []string
+}
+
+func (r *hostCertsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_host_certs"
+}
+
+func (r *hostCertsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Create SSH host certificates for many hosts with a single CA. " +
+			"Certificates are issued and renewed per host: adding, removing or changing a host only changes its own certificate",
+
+		Attributes: map[string]schema.Attribute{
+			"ca_private_key_pem": schema.StringAttribute{
+				CustomType: privateKeyPEMType{},
+				Required:   true,
+				Sensitive:  true,
+				Description: "Private key of the Certificate Authority (CA) used to sign the certificates, " +
+					"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"Changing it reissues all certificates.",
+			},
+			"validity_period_hours": schema.Int64Attribute{
+				Required: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				Description: "Number of hours, after initial issuing, that the certificates will remain valid for. " +
+					"Changing it reissues all certificates.",
+			},
+			"early_renewal_hours": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(0),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				Description: "The resource will consider a certificate to have expired the given number of hours " +
+					"before its actual expiry time, and reissue it when the Terraform configuration is next applied. (default: `0`)",
+			},
+			"early_renewal_fraction": earlyRenewalFractionAttribute(),
+			"early_renewal":          earlyRenewalAttribute(),
+			"early_renewal_jitter":   earlyRenewalJitterAttribute(),
+			"align_expiry_to":        alignExpiryToAttribute(),
+			"hosts": schema.MapNestedAttribute{
+				Required: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"public_key_openssh": schema.StringAttribute{
+							Required: true,
+							Description: "SSH host public key to sign, in authorized keys or " +
+								"[RFC 4716](https://datatracker.ietf.org/doc/html/rfc4716) format.",
+						},
+						"valid_principals": schema.ListAttribute{
+							ElementType: types.StringType,
+							Optional:    true,
+							Validators: []validator.List{
+								listvalidator.ValueStringsAre(principalValidator()),
+							},
+							Description: "List of host names the certificate is valid for. (default: the map key)",
+						},
+						"key_id": schema.StringAttribute{
+							Optional:    true,
+							Description: "Key ID of the certificate. (default: the map key)",
+						},
+					},
+				},
+				Description: "Map of hosts to issue certificates for. The map key identifies the host in `certs`.",
+			},
+			"certs": schema.MapNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"cert_authorized_key": schema.StringAttribute{
+							Computed:    true,
+							Description: "The certificate, in authorized keys format.",
+						},
+						"serial": schema.StringAttribute{
+							Computed:    true,
+							Description: "Serial number of the certificate.",
+						},
+						"validity_start_time": schema.StringAttribute{
+							Computed: true,
+							Description: "The time after which the certificate is valid, " +
+								"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+						},
+						"validity_end_time": schema.StringAttribute{
+							Computed: true,
+							Description: "The time until which the certificate is invalid, " +
+								"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+						},
+						"public_key_fingerprint_sha256": schema.StringAttribute{
+							Computed:    true,
+							Description: "SHA256 fingerprint of the host public key.",
+						},
+					},
+				},
+				Description: "Map of issued certificates, with the same keys as `hosts`.",
+			},
+			"ca_public_key_openssh": schema.StringAttribute{
+				Computed:    true,
+				Description: "Public key of the Certificate Authority (CA), in authorized keys format.",
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Unique identifier for this resource: the SHA256 fingerprint of the CA public key at creation.",
+			},
+		},
+	}
+}
+
+func (r *hostCertsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+}
+
+func (r *hostCertsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var newState hostCertsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(issueHostCerts(ctx, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+func (r *hostCertsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+}
+
+func (r *hostCertsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var newState hostCertsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(issueHostCerts(ctx, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+func (r *hostCertsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+// ModifyPlan keeps the certificate of each host that still matches its configuration and is not
+// ready for renewal. The certificates of all other hosts are planned to be issued.
+func (r *hostCertsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do if the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	resp.Diagnostics.Append(validateCertificateSchedule(ctx, req.Plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan hostCertsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	certsType := types.ObjectType{AttrTypes: hostCertsCertAttrTypes}
+	if plan.Hosts.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("certs"), types.MapUnknown(certsType))...)
+		return
+	}
+
+	var hosts map[string]hostCertsHostModel
+	resp.Diagnostics.Append(plan.Hosts.ElementsAs(ctx, &hosts, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Certificates can only be kept if the CA and validity period are known
+	var caPubKey ssh.PublicKey
+	caPublicKeyOpenSSH := types.StringUnknown()
+	if !plan.CAPrivateKeyPEM.IsUnknown() {
+		signer, diags := parseCASigner(plan.CAPrivateKeyPEM.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		caPubKey = signer.PublicKey()
+		caPublicKeyOpenSSH = types.StringValue(string(ssh.MarshalAuthorizedKey(caPubKey)))
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ca_public_key_openssh"), caPublicKeyOpenSSH)...)
+
+	// Certificates can only be kept if their validity period and renewal are known
+	schedule, scheduleKnown, diags := getCertificateSchedule(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var priorCerts map[string]hostCertsCertModel
+	if !req.State.Raw.IsNull() {
+		var state hostCertsResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !state.Certs.IsNull() && !state.Certs.IsUnknown() {
+			resp.Diagnostics.Append(state.Certs.ElementsAs(ctx, &priorCerts, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	certs := make(map[string]attr.Value, len(hosts))
+	for name, host := range hosts {
+		certs[name] = types.ObjectUnknown(hostCertsCertAttrTypes)
+
+		priorCert, ok := priorCerts[name]
+		if !ok || caPubKey == nil || !scheduleKnown {
+			continue
+		}
+		spec, known, diags := hostCertsSpecFromModel(ctx, name, host)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !known {
+			continue
+		}
+		certificate, err := parseCertificate(priorCert.CertAuthorizedKey.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to parse certificate of host %q from state", name), err.Error())
+			return
+		}
+		if !hostCertMatchesSpec(certificate, spec, caPubKey, schedule) {
+			continue
+		}
+
+		// The comment is not part of the certificate, so it can change without reissuing it
+		priorCert.CertAuthorizedKey = types.StringValue(marshalCertificate(certificate, spec.comment))
+		certValue, diags := types.ObjectValueFrom(ctx, hostCertsCertAttrTypes, priorCert)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		certs[name] = certValue
+	}
+
+	certsValue, diags := types.MapValue(certsType, certs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("certs"), certsValue)...)
+}
+
+// issueHostCerts issues the certificates that are unknown in the planned model, and keeps the others.
+func issueHostCerts(ctx context.Context, model *hostCertsResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	signer, d := parseCASigner(model.CAPrivateKeyPEM.ValueString())
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+	schedule := certificateSchedule{
+		validityPeriodHours: model.ValidityPeriodHours.ValueInt64(),
+		alignExpiryTo:       model.AlignExpiryTo.ValueString(),
+	}
+
+	var hosts map[string]hostCertsHostModel
+	diags.Append(model.Hosts.ElementsAs(ctx, &hosts, false)...)
+	plannedCerts := map[string]attr.Value{}
+	if !model.Certs.IsUnknown() && !model.Certs.IsNull() {
+		plannedCerts = model.Certs.Elements()
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	certs := make(map[string]attr.Value, len(hosts))
+	for name, host := range hosts {
+		if planned, ok := plannedCerts[name]; ok && !planned.IsUnknown() {
+			certs[name] = planned
+			continue
+		}
+
+		spec, _, d := hostCertsSpecFromModel(ctx, name, host)
+		diags.Append(d...)
+		if diags.HasError() {
+			return diags
+		}
+		certificate, err := signHostCert(signer, spec, schedule)
+		if err != nil {
+			diags.AddAttributeError(path.Root("hosts").AtMapKey(name), "Failed to sign certificate", err.Error())
+			return diags
+		}
+
+		validFromBytes, err := time.Unix(int64(certificate.ValidAfter), 0).MarshalText()
+		if err != nil {
+			diags.AddError("Failed to serialize validity start time", err.Error())
+			return diags
+		}
+		validToBytes, err := time.Unix(int64(certificate.ValidBefore), 0).MarshalText()
+		if err != nil {
+			diags.AddError("Failed to serialize validity end time", err.Error())
+			return diags
+		}
+		certValue, d := types.ObjectValueFrom(ctx, hostCertsCertAttrTypes, hostCertsCertModel{
+			CertAuthorizedKey:    types.StringValue(marshalCertificate(certificate, spec.comment)),
+			Serial:               types.StringValue(fmt.Sprintf("%d", certificate.Serial)),
+			ValidityStartTime:    types.StringValue(string(validFromBytes)),
+			ValidityEndTime:      types.StringValue(string(validToBytes)),
+			PublicKeyFingerprint: types.StringValue(ssh.FingerprintSHA256(certificate.Key)),
+		})
+		diags.Append(d...)
+		if diags.HasError() {
+			return diags
+		}
+		certs[name] = certValue
+	}
+
+	model.Certs, d = types.MapValue(types.ObjectType{AttrTypes: hostCertsCertAttrTypes}, certs)
+	diags.Append(d...)
+	model.CAPublicKeyOpenSSH = types.StringValue(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+	if model.ID.IsUnknown() {
+		model.ID = types.StringValue(ssh.FingerprintSHA256(signer.PublicKey()))
+	}
+	return diags
+}
+
+// parseCASigner parses the CA private key into a signer for certificates.
+func parseCASigner(caPrivateKeyPEM string) (ssh.Signer, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	caPrvKey, _, err := parsePrivateKeyPEM([]byte(caPrivateKeyPEM))
+	if err != nil {
+		diags.AddAttributeError(path.Root("ca_private_key_pem"), "Failed to parse CA private key PEM", err.Error())
+		return nil, diags
+	}
+	signer, err := ssh.NewSignerFromKey(caPrvKey)
+	if err != nil {
+		diags.AddAttributeError(path.Root("ca_private_key_pem"), "Failed to create signer with private key", err.Error())
+		return nil, diags
+	}
+	return signer, diags
+}
+
+// hostCertsSpecFromModel returns the certificate requested for a host, with defaults applied.
+// It returns false if any of the configuration of the host is not known yet.
+func hostCertsSpecFromModel(ctx context.Context, name string, host hostCertsHostModel) (hostCertsSpec, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if host.PublicKeyOpenSSH.IsUnknown() || host.ValidPrincipals.IsUnknown() || host.KeyID.IsUnknown() {
+		return hostCertsSpec{}, false, diags
+	}
+	for _, principal := range host.ValidPrincipals.Elements() {
+		if principal.IsUnknown() {
+			return hostCertsSpec{}, false, diags
+		}
+	}
+
+	pubKey, comment, err := parsePublicKeyOpenSSH(host.PublicKeyOpenSSH.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("hosts").AtMapKey(name).AtName("public_key_openssh"), "Failed to parse public key", err.Error())
+		return hostCertsSpec{}, false, diags
+	}
+	spec := hostCertsSpec{
+		pubKey:     pubKey,
+		comment:    comment,
+		keyID:      name,
+		principals: []string{name},
+	}
+	if !host.KeyID.IsNull() {
+		spec.keyID = host.KeyID.ValueString()
+	}
+	if !host.ValidPrincipals.IsNull() {
+		spec.principals = []string{}
+		diags.Append(host.ValidPrincipals.ElementsAs(ctx, &spec.principals, false)...)
+	}
+	return spec, !diags.HasError(), diags
+}
+
+// hostCertMatchesSpec reports whether an issued certificate still satisfies the requested
+// certificate, and is not ready for renewal.
+func hostCertMatchesSpec(certificate *ssh.Certificate, spec hostCertsSpec, caPubKey ssh.PublicKey, schedule certificateSchedule) bool {
+	validAfter := time.Unix(int64(certificate.ValidAfter), 0)
+	if !bytes.Equal(certificate.Key.Marshal(), spec.pubKey.Marshal()) ||
+		!bytes.Equal(certificate.SignatureKey.Marshal(), caPubKey.Marshal()) ||
+		certificate.KeyId != spec.keyID ||
+		!slices.Equal(certificate.ValidPrincipals, spec.principals) ||
+		int64(certificate.ValidBefore) != schedule.validityEnd(validAfter).Unix() {
+		return false
+	}
+
+	earlyRenewalTime, err := schedule.certificateRenewalTime(certificate)
+	if err != nil {
+		return false
+	}
+	return overridableTimeFunc().Before(earlyRenewalTime)
+}
+
+// signHostCert signs a host certificate valid from now, for the validity period of the schedule.
+func signHostCert(signer ssh.Signer, spec hostCertsSpec, schedule certificateSchedule) (*ssh.Certificate, error) {
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	now := time.Unix(overridableTimeFunc().Unix(), 0)
+	certificate := &ssh.Certificate{
+		Key:             spec.pubKey,
+		Serial:          serial,
+		CertType:        ssh.HostCert,
+		KeyId:           spec.keyID,
+		ValidPrincipals: spec.principals,
+		ValidAfter:      uint64(now.Unix()),
+		ValidBefore:     uint64(schedule.validityEnd(now).Unix()),
+	}
+	if err := certificate.SignCert(rand.Reader, signer); err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
@@ -0,0 +1,204 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestResourceHostCerts(t *testing.T) {
+	serials := map[string]string{}
+	saveSerial := func(host string) r.TestCheckFunc {
+		return r.TestCheckResourceAttrWith("ssh_host_certs.test", "certs."+host+".serial", func(value string) error {
+			serials[host] = value
+			return nil
+		})
+	}
+	checkSerial := func(host string, changed bool) r.TestCheckFunc {
+		return r.TestCheckResourceAttrWith("ssh_host_certs.test", "certs."+host+".serial", func(value string) error {
+			if (value != serials[host]) != changed {
+				return fmt.Errorf("certificate of %s reissued: %t, wanted %t", host, value != serials[host], changed)
+			}
+			serials[host] = value
+			return nil
+		})
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 setTimeForTest("2023-01-01T12:00:00Z"),
+		Steps: []r.TestStep{
+			{
+				Config: hostCertsConfig(`
+			"web1" = {
+				public_key_openssh = %[1]q
+			}
+			"web2" = {
+				public_key_openssh = %[1]q
+				valid_principals   = ["web2.example.com", "web2"]
+				key_id             = "web2-host"
+			}`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("ssh_host_certs.test", "certs.%", "2"),
+					r.TestCheckResourceAttr("ssh_host_certs.test", "ca_public_key_openssh", inputCAPublicKeyOpenSSH+"\n"),
+					r.TestCheckResourceAttr("ssh_host_certs.test", "certs.web1.validity_start_time", "2023-01-01T12:00:00Z"),
+					r.TestCheckResourceAttr("ssh_host_certs.test", "certs.web1.validity_end_time", "2023-01-01T22:00:00Z"),
+					r.TestCheckResourceAttr("ssh_host_certs.test", "certs.web1.public_key_fingerprint_sha256", "SHA256:7iAinM8X0yF4aPfJWdLk6BimJH7OhkvFv72fHcdUgR8"),
+					func(s *terraform.State) error {
+						attributes := s.RootModule().Resources["ssh_host_certs.test"].Primary.Attributes
+						for host, expected := range map[string]struct {
+							keyID      string
+							principals []string
+						}{
+							"web1": {"web1", []string{"web1"}},
+							"web2": {"web2-host", []string{"web2.example.com", "web2"}},
+						} {
+							certificate, err := parseCertificate(attributes["certs."+host+".cert_authorized_key"])
+							if err != nil {
+								return err
+							}
+							if certificate.CertType != ssh.HostCert || certificate.KeyId != expected.keyID || !reflect.DeepEqual(certificate.ValidPrincipals, expected.principals) {
+								return fmt.Errorf("incorrect certificate for %s:\n%s", host, certificateText(certificate))
+							}
+						}
+						return nil
+					},
+					saveSerial("web1"),
+					saveSerial("web2"),
+				),
+			},
+			{
+				Config: hostCertsConfig(`
+			"web1" = {
+				public_key_openssh = %[1]q
+			}
+			"web2" = {
+				public_key_openssh = %[1]q
+				valid_principals   = ["web2.example.com", "web2"]
+				key_id             = "web2-host"
+			}
+			"web3" = {
+				public_key_openssh = %[1]q
+			}`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("ssh_host_certs.test", "certs.%", "3"),
+					checkSerial("web1", false),
+					checkSerial("web2", false),
+					saveSerial("web3"),
+				),
+			},
+			{
+				Config: hostCertsConfig(`
+			"web1" = {
+				public_key_openssh = %[1]q
+			}
+			"web2" = {
+				public_key_openssh = %[1]q
+				valid_principals   = ["web2.example.com"]
+				key_id             = "web2-host"
+			}`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("ssh_host_certs.test", "certs.%", "2"),
+					r.TestCheckNoResourceAttr("ssh_host_certs.test", "certs.web3.serial"),
+					checkSerial("web1", false),
+					checkSerial("web2", true),
+				),
+			},
+			{
+				PreConfig: setTimeForTest("2023-01-01T21:00:00Z"),
+				Config: hostCertsConfig(`
+			"web1" = {
+				public_key_openssh = %[1]q
+			}
+			"web2" = {
+				public_key_openssh = %[1]q
+				valid_principals   = ["web2.example.com"]
+				key_id             = "web2-host"
+			}`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("ssh_host_certs.test", "certs.web1.validity_end_time", "2023-01-02T07:00:00Z"),
+					checkSerial("web1", true),
+					checkSerial("web2", true),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceHostCertsRenewalSchedule(t *testing.T) {
+	hosts := []string{"web1", "web2", "web3"}
+	serials := map[string]string{}
+	checkReissued := func(changed bool) r.TestCheckFunc {
+		return func(s *terraform.State) error {
+			attributes := s.RootModule().Resources["ssh_host_certs.test"].Primary.Attributes
+			for _, host := range hosts {
+				serial := attributes["certs."+host+".serial"]
+				if (serial != serials[host]) != changed {
+					return fmt.Errorf("certificate of %s reissued: %t, wanted %t", host, serial != serials[host], changed)
+				}
+				serials[host] = serial
+			}
+			return nil
+		}
+	}
+	config := strings.Replace(hostCertsConfig(`
+			"web1" = {
+				public_key_openssh = %[1]q
+			}
+			"web2" = {
+				public_key_openssh = %[1]q
+			}
+			"web3" = {
+				public_key_openssh = %[1]q
+			}`), `early_renewal_hours   = 2`, `early_renewal_hours   = 2
+		early_renewal_jitter  = "1h"
+		align_expiry_to       = "day"`, 1)
+
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 setTimeForTest("2023-01-01T12:00:00Z"),
+		Steps: []r.TestStep{
+			{
+				Config: config,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("ssh_host_certs.test", "certs.web1.validity_end_time", "2023-01-02T00:00:00Z"),
+					r.TestCheckResourceAttr("ssh_host_certs.test", "certs.web3.validity_end_time", "2023-01-02T00:00:00Z"),
+					checkReissued(true),
+				),
+			},
+			{
+				PreConfig: setTimeForTest("2023-01-01T20:59:00Z"),
+				Config:    config,
+				Check:     checkReissued(false),
+			},
+			{
+				PreConfig: setTimeForTest("2023-01-01T22:00:00Z"),
+				Config:    config,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("ssh_host_certs.test", "certs.web2.validity_end_time", "2023-01-03T00:00:00Z"),
+					checkReissued(true),
+				),
+			},
+		},
+	})
+}
+
+func hostCertsConfig(hosts string) string {
+	return providerConfig + fmt.Sprintf(`
+	resource "ssh_host_certs" "test" {
+		ca_private_key_pem = <<EOT
+%[2]s
+EOT
+		validity_period_hours = 10
+		early_renewal_hours   = 2
+		hosts = {`+hosts+`
+		}
+	}`, inputPublicKeyOpenSSH, inputPrivateKey)
+}
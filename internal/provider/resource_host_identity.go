@@ -0,0 +1,584 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/randomcoww/terraform-provider-ssh/internal/provider/attribute_plan_modifier_bool"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &hostIdentityResource{}
+var _ resource.ResourceWithModifyPlan = &hostIdentityResource{}
+
+func NewHostIdentityResource() resource.Resource {
+	return &hostIdentityResource{}
+}
+
+// hostIdentityResource defines the resource implementation.
+type hostIdentityResource struct{}
+
+// hostIdentityResourceModel describes the resource data model.
+type hostIdentityResourceModel struct {
+	CAPrivateKeyPEM      privateKeyPEMValue `tfsdk:"ca_private_key_pem"`
+	Algorithms           types.List         `tfsdk:"algorithms"`
+	RSABits              types.Int64        `tfsdk:"rsa_bits"`
+	ECDSACurve           types.String       `tfsdk:"ecdsa_curve"`
+	KeyID                types.String       `tfsdk:"key_id"`
+	ValidPrincipals      types.List         `tfsdk:"valid_principals"`
+	ValidityPeriodHours  types.Int64        `tfsdk:"validity_period_hours"`
+	EarlyRenewalHours    types.Int64        `tfsdk:"early_renewal_hours"`
+	EarlyRenewalFraction types.Float64      `tfsdk:"early_renewal_fraction"`
+	EarlyRenewal         types.String       `tfsdk:"early_renewal"`
+	EarlyRenewalJitter   types.String       `tfsdk:"early_renewal_jitter"`
+	AlignExpiryTo        types.String       `tfsdk:"align_expiry_to"`
+	HostKeyDir           types.String       `tfsdk:"host_key_dir"`
+	PrivateKeysOpenSSH   types.Map          `tfsdk:"private_keys_openssh"`
+	PublicKeysOpenSSH    types.Map          `tfsdk:"public_keys_openssh"`
+	CertAuthorizedKeys   types.Map          `tfsdk:"cert_authorized_keys"`
+	Serial               types.String       `tfsdk:"serial"`
+	ReadyForRenewal      types.Bool         `tfsdk:"ready_for_renewal"`
+	ValidityStartTime    types.String       `tfsdk:"validity_start_time"`
+	ValidityEndTime      types.String       `tfsdk:"validity_end_time"`
+	CAPublicKeyOpenSSH   types.String       `tfsdk:"ca_public_key_openssh"`
+	SSHDConfig           types.String       `tfsdk:"sshd_config"`
+	KnownHosts           types.String       `tfsdk:"known_hosts"`
+	ID                   types.String       `tfsdk:"id"`
+}
+
+func (r *hostIdentityResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_host_identity"
+}
+
+func (r *hostIdentityResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Create SSH host keys, sign them with a host Certificate Authority (CA), " +
+			"and generate the matching sshd_config and known_hosts entries. " +
+			"The host keys are kept when the certificates are renewed or reissued",
+
+		Attributes: map[string]schema.Attribute{
+			"ca_private_key_pem": schema.StringAttribute{
+				CustomType: privateKeyPEMType{},
+				Required:   true,
+				Sensitive:  true,
+				Description: "Private key of the Certificate Authority (CA) used to sign the certificates, " +
+					"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"Changing it reissues the certificates.",
+			},
+			"algorithms": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.UniqueValues(),
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(RSA.String(), ECDSA.String(), ED25519.String())),
+				},
+				Description: "List of algorithms to generate host keys for: `RSA`, `ECDSA` or `ED25519`. " +
+					"The outputs are keyed by algorithm.",
+			},
+			"rsa_bits": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(3072),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(2048),
+				},
+				Description: "Size of the generated RSA host key, in bits. (default: `3072`)",
+			},
+			"ecdsa_curve": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(P256.String()),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(P256.String(), P384.String(), P521.String()),
+				},
+				Description: "Curve of the generated ECDSA host key: `P256`, `P384` or `P521`. (default: `P256`)",
+			},
+			"key_id": schema.StringAttribute{
+				Required: true,
+				Description: "Key ID of the certificates. " +
+					"Changing it reissues the certificates.",
+			},
+			"valid_principals": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(principalValidator()),
+				},
+				Description: "List of host names the certificates are valid for, also used as the hosts of `known_hosts`. " +
+					"Changing it reissues the certificates.",
+			},
+			"validity_period_hours": schema.Int64Attribute{
+				Required: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				Description: "Number of hours, after initial issuing, that the certificates will remain valid for. " +
+					"Changing it reissues the certificates.",
+			},
+			"early_renewal_hours": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(0),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				Description: "The resource will consider the certificates to have expired the given number of hours " +
+					"before their actual expiry time, and reissue them for the same host keys when the Terraform " +
+					"configuration is next applied. (default: `0`)",
+			},
+			"early_renewal_fraction": earlyRenewalFractionAttribute(),
+			"early_renewal":          earlyRenewalAttribute(),
+			"early_renewal_jitter":   earlyRenewalJitterAttribute(),
+			"align_expiry_to":        alignExpiryToAttribute(),
+			"host_key_dir": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("/etc/ssh"),
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				Description: "Directory of the host key files referenced in `sshd_config`. " +
+					"The files are named `ssh_host_<algorithm>_key` and `ssh_host_<algorithm>_key-cert.pub`, " +
+					"with the algorithm in lower case, as created by `ssh-keygen -A`. (default: `/etc/ssh`)",
+			},
+			"private_keys_openssh": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Map of host private keys in OpenSSH format, keyed by algorithm.",
+			},
+			"public_keys_openssh": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Map of host public keys in authorized keys format, keyed by algorithm.",
+			},
+			"cert_authorized_keys": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Map of host certificates in authorized keys format, keyed by algorithm. " +
+					"All certificates share the serial number and validity period.",
+			},
+			"serial": schema.StringAttribute{
+				Computed:    true,
+				Description: "Serial number of the certificates.",
+			},
+			"ready_for_renewal": schema.BoolAttribute{
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					attribute_plan_modifier_bool.ReadyForRenewal(),
+				},
+				Description: "Are the certificates either expired (i.e. beyond the `validity_period_hours`) " +
+					"or ready for an early renewal (i.e. within `early_renewal_hours`, `early_renewal_fraction` or `early_renewal`)?",
+			},
+			"validity_start_time": schema.StringAttribute{
+				Computed: true,
+				Description: "The time after which the certificates are valid, " +
+					"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+			},
+			"validity_end_time": schema.StringAttribute{
+				Computed: true,
+				Description: "The time until which the certificates are invalid, " +
+					"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+			},
+			"ca_public_key_openssh": schema.StringAttribute{
+				Computed:    true,
+				Description: "Public key of the Certificate Authority (CA), in authorized keys format.",
+			},
+			"sshd_config": schema.StringAttribute{
+				Computed:    true,
+				Description: "`HostKey` and `HostCertificate` lines of sshd_config for the host keys in `host_key_dir`.",
+			},
+			"known_hosts": schema.StringAttribute{
+				Computed:    true,
+				Description: "known_hosts lines of the host public keys for `valid_principals`.",
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Unique identifier for this resource: the SHA256 fingerprint of the first host public key.",
+			},
+		},
+	}
+}
+
+func (r *hostIdentityResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+}
+
+func (r *hostIdentityResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var newState hostIdentityResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var algorithms []string
+	resp.Diagnostics.Append(newState.Algorithms.ElementsAs(ctx, &algorithms, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	privateKeys := make(map[string]attr.Value, len(algorithms))
+	publicKeys := make(map[string]attr.Value, len(algorithms))
+	for i, algorithm := range algorithms {
+		prvKey, err := generatePrivateKey(Algorithm(algorithm), int(newState.RSABits.ValueInt64()), ECDSACurve(newState.ECDSACurve.ValueString()))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("algorithms").AtListIndex(i), "Failed to generate host key", err.Error())
+			return
+		}
+		privateKeyOpenSSH, err := marshalPrivateKeyOpenSSH(prvKey, "")
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to serialize host private key", err.Error())
+			return
+		}
+		pubKey, err := ssh.NewPublicKey(prvKey.Public())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to create host public key", err.Error())
+			return
+		}
+		if i == 0 {
+			newState.ID = types.StringValue(ssh.FingerprintSHA256(pubKey))
+		}
+		privateKeys[algorithm] = types.StringValue(privateKeyOpenSSH)
+		publicKeys[algorithm] = types.StringValue(string(ssh.MarshalAuthorizedKey(pubKey)))
+	}
+
+	var diags diag.Diagnostics
+	newState.PrivateKeysOpenSSH, diags = types.MapValue(types.StringType, privateKeys)
+	resp.Diagnostics.Append(diags...)
+	newState.PublicKeysOpenSSH, diags = types.MapValue(types.StringType, publicKeys)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(signHostIdentity(ctx, &newState)...)
+	resp.Diagnostics.Append(updateHostIdentityConfig(ctx, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+func (r *hostIdentityResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	modifyStateIfCertificateReadyForRenewal(ctx, req, resp, path.Root("serial"))
+}
+
+// Update reissues the certificates for the existing host keys if they are planned to change.
+func (r *hostIdentityResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var newState hostIdentityResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if newState.CertAuthorizedKeys.IsUnknown() {
+		resp.Diagnostics.Append(signHostIdentity(ctx, &newState)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	resp.Diagnostics.Append(updateHostIdentityConfig(ctx, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+func (r *hostIdentityResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+// ModifyPlan keeps the certificates if they still match the configuration and are not ready
+// for renewal, and plans them to be reissued for the same host keys otherwise.
+func (r *hostIdentityResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do if the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	resp.Diagnostics.Append(validateCertificateSchedule(ctx, req.Plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan hostIdentityResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// New host keys are generated on creation
+	if req.State.Raw.IsNull() || plan.PublicKeysOpenSSH.IsUnknown() {
+		resp.Diagnostics.Append(updateHostIdentityConfig(ctx, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+		return
+	}
+
+	var state hostIdentityResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schedule, scheduleKnown, diags := getCertificateSchedule(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	keep := false
+	if scheduleKnown {
+		keep, diags = hostIdentityCertsMatchPlan(ctx, &plan, &state, schedule)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if keep {
+		plan.CertAuthorizedKeys = state.CertAuthorizedKeys
+		plan.Serial = state.Serial
+		plan.ValidityStartTime = state.ValidityStartTime
+		plan.ValidityEndTime = state.ValidityEndTime
+		plan.CAPublicKeyOpenSSH = state.CAPublicKeyOpenSSH
+	} else {
+		plan.CertAuthorizedKeys = types.MapUnknown(types.StringType)
+		plan.Serial = types.StringUnknown()
+		plan.ValidityStartTime = types.StringUnknown()
+		plan.ValidityEndTime = types.StringUnknown()
+		plan.CAPublicKeyOpenSSH = types.StringUnknown()
+	}
+
+	resp.Diagnostics.Append(updateHostIdentityConfig(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+// hostIdentityCertsMatchPlan reports whether the certificates in the state still satisfy the
+// planned configuration and schedule, and are not ready for renewal.
+func hostIdentityCertsMatchPlan(ctx context.Context, plan, state *hostIdentityResourceModel, schedule certificateSchedule) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if plan.CAPrivateKeyPEM.IsUnknown() || plan.KeyID.IsUnknown() || plan.ValidPrincipals.IsUnknown() ||
+		state.CertAuthorizedKeys.IsNull() || state.CertAuthorizedKeys.IsUnknown() {
+		return false, diags
+	}
+	for _, principal := range plan.ValidPrincipals.Elements() {
+		if principal.IsUnknown() {
+			return false, diags
+		}
+	}
+
+	signer, d := parseCASigner(plan.CAPrivateKeyPEM.ValueString())
+	diags.Append(d...)
+	if diags.HasError() {
+		return false, diags
+	}
+	spec := hostCertsSpec{
+		keyID: plan.KeyID.ValueString(),
+	}
+	diags.Append(plan.ValidPrincipals.ElementsAs(ctx, &spec.principals, false)...)
+	if diags.HasError() {
+		return false, diags
+	}
+
+	for algorithm, v := range state.CertAuthorizedKeys.Elements() {
+		vstr, ok := v.(types.String)
+		if !ok {
+			return false, diags
+		}
+		certificate, err := parseCertificate(vstr.ValueString())
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Failed to parse %s certificate from state", algorithm), err.Error())
+			return false, diags
+		}
+		spec.pubKey = certificate.Key
+		if !hostCertMatchesSpec(certificate, spec, signer.PublicKey(), schedule) {
+			return false, diags
+		}
+	}
+	return true, diags
+}
+
+// signHostIdentity issues the certificates of all host public keys in the model, with a shared
+// serial number and validity period.
+func signHostIdentity(ctx context.Context, model *hostIdentityResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	signer, d := parseCASigner(model.CAPrivateKeyPEM.ValueString())
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+	schedule := certificateSchedule{
+		validityPeriodHours: model.ValidityPeriodHours.ValueInt64(),
+		alignExpiryTo:       model.AlignExpiryTo.ValueString(),
+	}
+	validAfter := time.Unix(overridableTimeFunc().Unix(), 0)
+	validBefore := schedule.validityEnd(validAfter)
+
+	serial, err := randomSerial()
+	if err != nil {
+		diags.AddError("Failed to generate serial number", err.Error())
+		return diags
+	}
+	template := ssh.Certificate{
+		Serial:      serial,
+		CertType:    ssh.HostCert,
+		KeyId:       model.KeyID.ValueString(),
+		ValidAfter:  uint64(validAfter.Unix()),
+		ValidBefore: uint64(validBefore.Unix()),
+	}
+	diags.Append(model.ValidPrincipals.ElementsAs(ctx, &template.ValidPrincipals, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	certs := make(map[string]attr.Value, len(model.PublicKeysOpenSSH.Elements()))
+	for algorithm, v := range model.PublicKeysOpenSSH.Elements() {
+		vstr, ok := v.(types.String)
+		if !ok {
+			continue
+		}
+		pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(vstr.ValueString()))
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Failed to parse %s host public key", algorithm), err.Error())
+			return diags
+		}
+		certificate := template
+		certificate.Key = pubKey
+		if err := certificate.SignCert(rand.Reader, signer); err != nil {
+			diags.AddError("Failed sign cert", err.Error())
+			return diags
+		}
+		certs[algorithm] = types.StringValue(marshalCertificate(&certificate, ""))
+	}
+
+	validFromBytes, err := time.Unix(int64(template.ValidAfter), 0).MarshalText()
+	if err != nil {
+		diags.AddError("Failed to serialize validity start time", err.Error())
+		return diags
+	}
+	validToBytes, err := time.Unix(int64(template.ValidBefore), 0).MarshalText()
+	if err != nil {
+		diags.AddError("Failed to serialize validity end time", err.Error())
+		return diags
+	}
+
+	model.CertAuthorizedKeys, d = types.MapValue(types.StringType, certs)
+	diags.Append(d...)
+	model.Serial = types.StringValue(fmt.Sprintf("%d", serial))
+	model.ValidityStartTime = types.StringValue(string(validFromBytes))
+	model.ValidityEndTime = types.StringValue(string(validToBytes))
+	model.CAPublicKeyOpenSSH = types.StringValue(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+	return diags
+}
+
+// updateHostIdentityConfig sets `sshd_config` from the algorithms and `host_key_dir`, and
+// `known_hosts` from the host public keys, as far as they are known.
+func updateHostIdentityConfig(ctx context.Context, model *hostIdentityResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	model.SSHDConfig = types.StringUnknown()
+	model.KnownHosts = types.StringUnknown()
+	if model.Algorithms.IsUnknown() {
+		return diags
+	}
+	var algorithms []string
+	diags.Append(model.Algorithms.ElementsAs(ctx, &algorithms, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if !model.HostKeyDir.IsUnknown() {
+		var sshdConfig strings.Builder
+		for _, algorithm := range algorithms {
+			hostKeyPath := strings.TrimSuffix(model.HostKeyDir.ValueString(), "/") + "/" + hostKeyFileName(Algorithm(algorithm))
+			fmt.Fprintf(&sshdConfig, "HostKey %s\n", hostKeyPath)
+			fmt.Fprintf(&sshdConfig, "HostCertificate %s-cert.pub\n", hostKeyPath)
+		}
+		model.SSHDConfig = types.StringValue(sshdConfig.String())
+	}
+
+	if model.ValidPrincipals.IsUnknown() || model.PublicKeysOpenSSH.IsUnknown() {
+		return diags
+	}
+	var hosts []string
+	diags.Append(model.ValidPrincipals.ElementsAs(ctx, &hosts, false)...)
+	if diags.HasError() {
+		return diags
+	}
+	for i, host := range hosts {
+		hosts[i] = knownhosts.Normalize(host)
+	}
+	publicKeys := model.PublicKeysOpenSSH.Elements()
+
+	var knownHosts strings.Builder
+	for _, algorithm := range algorithms {
+		v, ok := publicKeys[algorithm].(types.String)
+		if !ok {
+			continue
+		}
+		pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(v.ValueString()))
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Failed to parse %s host public key", algorithm), err.Error())
+			return diags
+		}
+		for _, line := range knownHostsLines("", hosts, pubKey, "", false, nil) {
+			knownHosts.WriteString(line + "\n")
+		}
+	}
+	model.KnownHosts = types.StringValue(knownHosts.String())
+	return diags
+}
+
+// hostKeyFileName returns the file name of the host key of an algorithm, as created by `ssh-keygen -A`.
+func hostKeyFileName(algorithm Algorithm) string {
+	return "ssh_host_" + strings.ToLower(algorithm.String()) + "_key"
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestResourceHostIdentity(t *testing.T) {
+	var serial, publicKey string
+	checkReissued := func(changed bool) r.TestCheckFunc {
+		return func(s *terraform.State) error {
+			attributes := s.RootModule().Resources["ssh_host_identity.test"].Primary.Attributes
+			if (attributes["serial"] != serial) != changed {
+				return fmt.Errorf("certificates reissued: %t, wanted %t", attributes["serial"] != serial, changed)
+			}
+			if attributes["public_keys_openssh.ED25519"] != publicKey {
+				return fmt.Errorf("host key changed")
+			}
+			serial = attributes["serial"]
+			return nil
+		}
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 setTimeForTest("2023-01-01T12:00:00Z"),
+		Steps: []r.TestStep{
+			{
+				Config: hostIdentityConfig(`["host1.example.com", "192.168.1.10"]`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("ssh_host_identity.test", "cert_authorized_keys.%", "2"),
+					r.TestCheckResourceAttr("ssh_host_identity.test", "ca_public_key_openssh", inputCAPublicKeyOpenSSH+"\n"),
+					r.TestCheckResourceAttr("ssh_host_identity.test", "validity_start_time", "2023-01-01T12:00:00Z"),
+					r.TestCheckResourceAttr("ssh_host_identity.test", "validity_end_time", "2023-01-01T22:00:00Z"),
+					r.TestCheckResourceAttr("ssh_host_identity.test", "sshd_config", `HostKey /etc/ssh/ssh_host_ed25519_key
+HostCertificate /etc/ssh/ssh_host_ed25519_key-cert.pub
+HostKey /etc/ssh/ssh_host_ecdsa_key
+HostCertificate /etc/ssh/ssh_host_ecdsa_key-cert.pub
+`),
+					func(s *terraform.State) error {
+						attributes := s.RootModule().Resources["ssh_host_identity.test"].Primary.Attributes
+						var knownHosts []string
+						for _, algorithm := range []string{"ED25519", "ECDSA"} {
+							prvKey, err := ssh.ParseRawPrivateKey([]byte(attributes["private_keys_openssh."+algorithm]))
+							if err != nil {
+								return err
+							}
+							signer, err := ssh.NewSignerFromKey(prvKey)
+							if err != nil {
+								return err
+							}
+							certificate, err := parseCertificate(attributes["cert_authorized_keys."+algorithm])
+							if err != nil {
+								return err
+							}
+							if !bytes.Equal(certificate.Key.Marshal(), signer.PublicKey().Marshal()) {
+								return fmt.Errorf("certificate for %s does not match the private key", algorithm)
+							}
+							if certificate.CertType != ssh.HostCert || certificate.KeyId != "host1" ||
+								fmt.Sprintf("%d", certificate.Serial) != attributes["serial"] ||
+								!reflect.DeepEqual(certificate.ValidPrincipals, []string{"host1.example.com", "192.168.1.10"}) {
+								return fmt.Errorf("incorrect certificate for %s:\n%s", algorithm, certificateText(certificate))
+							}
+							publicKey := strings.TrimSuffix(attributes["public_keys_openssh."+algorithm], "\n")
+							knownHosts = append(knownHosts, "host1.example.com,192.168.1.10 "+publicKey+"\n")
+						}
+						if attributes["known_hosts"] != strings.Join(knownHosts, "") {
+							return fmt.Errorf("incorrect known_hosts:\n%s", attributes["known_hosts"])
+						}
+						if attributes["id"] != ssh.FingerprintSHA256(mustParseAuthorizedKey(attributes["public_keys_openssh.ED25519"])) {
+							return fmt.Errorf("incorrect id: %s", attributes["id"])
+						}
+						serial = attributes["serial"]
+						publicKey = attributes["public_keys_openssh.ED25519"]
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig: setTimeForTest("2023-01-01T19:00:00Z"),
+				Config:    hostIdentityConfig(`["host1.example.com", "192.168.1.10"]`),
+				Check:     checkReissued(false),
+			},
+			{
+				PreConfig: setTimeForTest("2023-01-01T19:00:00Z"),
+				Config:    hostIdentityConfig(`["host1.example.com"]`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					checkReissued(true),
+					r.TestCheckResourceAttrWith("ssh_host_identity.test", "known_hosts", func(value string) error {
+						if !strings.HasPrefix(value, "host1.example.com ssh-ed25519 ") {
+							return fmt.Errorf("incorrect known_hosts:\n%s", value)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				PreConfig: setTimeForTest("2023-01-02T04:00:00Z"),
+				Config:    hostIdentityConfig(`["host1.example.com"]`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					checkReissued(true),
+					r.TestCheckResourceAttr("ssh_host_identity.test", "validity_end_time", "2023-01-02T14:00:00Z"),
+					r.TestCheckResourceAttr("ssh_host_identity.test", "ready_for_renewal", "false"),
+				),
+			},
+		},
+	})
+}
+
+func hostIdentityConfig(validPrincipals string) string {
+	return providerConfig + fmt.Sprintf(`
+	resource "ssh_host_identity" "test" {
+		ca_private_key_pem = <<EOT
+%s
+EOT
+		algorithms            = ["ED25519", "ECDSA"]
+		key_id                = "host1"
+		valid_principals      = %s
+		validity_period_hours = 10
+		early_renewal_hours   = 2
+	}`, inputPrivateKey, validPrincipals)
+}
+
+func mustParseAuthorizedKey(key string) ssh.PublicKey {
+	pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+	if err != nil {
+		panic(err)
+	}
+	return pubKey
+}
@@ -0,0 +1,327 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &krlResource{}
+
+func NewKRLResource() resource.Resource {
+	return &krlResource{}
+}
+
+// krlResource defines the resource implementation.
+type krlResource struct{}
+
+// krlResourceModel describes the resource data model.
+type krlResourceModel struct {
+	Version                   types.Int64  `tfsdk:"version"`
+	Comment                   types.String `tfsdk:"comment"`
+	Certificates              types.List   `tfsdk:"certificates"`
+	RevokedKeys               types.List   `tfsdk:"revoked_keys"`
+	RevokedFingerprintsSHA256 types.List   `tfsdk:"revoked_fingerprints_sha256"`
+	GeneratedTime             types.String `tfsdk:"generated_time"`
+	KRLBase64                 types.String `tfsdk:"krl_base64"`
+	ID                        types.String `tfsdk:"id"`
+}
+
+// krlCertificatesModel describes the certificates revoked for a CA.
+type krlCertificatesModel struct {
+	CAPublicKeyOpenSSH types.String `tfsdk:"ca_public_key_openssh"`
+	Serials            types.List   `tfsdk:"serials"`
+	SerialRanges       types.List   `tfsdk:"serial_ranges"`
+	KeyIDs             types.List   `tfsdk:"key_ids"`
+}
+
+// krlSerialRangeModel describes an inclusive range of certificate serial numbers.
+type krlSerialRangeModel struct {
+	Min types.String `tfsdk:"min"`
+	Max types.String `tfsdk:"max"`
+}
+
+func (r *krlResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_krl"
+}
+
+func (r *krlResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Create OpenSSH Key Revocation List (KRL)",
+
+		Attributes: map[string]schema.Attribute{
+			"version": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(0),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				Description: "Version number of the KRL. (default: `0`)",
+			},
+			"comment": schema.StringAttribute{
+				Optional:    true,
+				Description: "Comment embedded in the KRL.",
+			},
+			"certificates": schema.ListNestedAttribute{
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ca_public_key_openssh": schema.StringAttribute{
+							Optional: true,
+							Description: "Public key of the Certificate Authority (CA) that signed the revoked certificates, " +
+								"in authorized keys format. If not set, the serial numbers and `key_ids` are revoked for certificates signed by any CA, as in OpenSSH.",
+						},
+						"serials": schema.ListAttribute{
+							ElementType: types.StringType,
+							Optional:    true,
+							Validators: []validator.List{
+								listvalidator.ValueStringsAre(serialValidator()),
+							},
+							Description: "List of revoked certificate serial numbers.",
+						},
+						"serial_ranges": schema.ListNestedAttribute{
+							Optional: true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"min": schema.StringAttribute{
+										Required: true,
+										Validators: []validator.String{
+											serialValidator(),
+										},
+										Description: "First revoked serial number.",
+									},
+									"max": schema.StringAttribute{
+										Required: true,
+										Validators: []validator.String{
+											serialValidator(),
+										},
+										Description: "Last revoked serial number.",
+									},
+								},
+							},
+							Description: "List of inclusive ranges of revoked certificate serial numbers.",
+						},
+						"key_ids": schema.ListAttribute{
+							ElementType: types.StringType,
+							Optional:    true,
+							Description: "List of revoked certificate key IDs.",
+						},
+					},
+				},
+				Description: "List of certificates to revoke, grouped by the Certificate Authority (CA) that signed them.",
+			},
+			"revoked_keys": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "List of public keys or certificates to revoke, in authorized keys format. " +
+					"Certificates are revoked by serial number, or by key ID if they have no serial number.",
+			},
+			"revoked_fingerprints_sha256": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.RegexMatches(sha256FingerprintRegexp, "must be a SHA256 fingerprint, such as `SHA256:...`")),
+				},
+				Description: "List of SHA256 fingerprints of public keys to revoke, in the format printed by `ssh-keygen -l`.",
+			},
+			"generated_time": schema.StringAttribute{
+				Computed: true,
+				Description: "The time the KRL was generated, " +
+					"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+			},
+			"krl_base64": schema.StringAttribute{
+				Computed: true,
+				Description: "KRL in base64 encoded binary format, as generated by `ssh-keygen -k`. " +
+					"Decode it with `base64decode` into the file referenced by the `RevokedKeys` server option.",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for this resource: the SHA256 checksum of the KRL.",
+			},
+		},
+	}
+}
+
+func (r *krlResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+}
+
+func (r *krlResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var newState krlResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(generateKRL(ctx, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+func (r *krlResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+}
+
+func (r *krlResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var newState krlResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(generateKRL(ctx, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+func (r *krlResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+// generateKRL builds the KRL from the revocations in the model, and sets the computed attributes.
+func generateKRL(ctx context.Context, model *krlResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	now := overridableTimeFunc()
+	k := &krl{
+		version:       uint64(model.Version.ValueInt64()),
+		generatedDate: uint64(now.Unix()),
+		comment:       model.Comment.ValueString(),
+	}
+
+	if !model.Certificates.IsNull() {
+		var certificates []krlCertificatesModel
+		diags.Append(model.Certificates.ElementsAs(ctx, &certificates, false)...)
+		if diags.HasError() {
+			return diags
+		}
+
+		for i, certificatesModel := range certificates {
+			attributePath := path.Root("certificates").AtListIndex(i)
+
+			var caKey []byte
+			if !certificatesModel.CAPublicKeyOpenSSH.IsNull() {
+				pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(certificatesModel.CAPublicKeyOpenSSH.ValueString()))
+				if err != nil {
+					diags.AddAttributeError(attributePath.AtName("ca_public_key_openssh"), "Failed to parse CA public key", err.Error())
+					return diags
+				}
+				caKey = pubKey.Marshal()
+			}
+			c := k.certificatesForCA(caKey)
+
+			var serials []string
+			if !certificatesModel.Serials.IsNull() {
+				diags.Append(certificatesModel.Serials.ElementsAs(ctx, &serials, false)...)
+			}
+			var serialRanges []krlSerialRangeModel
+			if !certificatesModel.SerialRanges.IsNull() {
+				diags.Append(certificatesModel.SerialRanges.ElementsAs(ctx, &serialRanges, false)...)
+			}
+			if !certificatesModel.KeyIDs.IsNull() {
+				var keyIDs []string
+				diags.Append(certificatesModel.KeyIDs.ElementsAs(ctx, &keyIDs, false)...)
+				c.keyIDs = append(c.keyIDs, keyIDs...)
+			}
+			if diags.HasError() {
+				return diags
+			}
+
+			if caKey == nil && (len(serials) > 0 || len(serialRanges) > 0) {
+				diags.AddAttributeError(attributePath.AtName("ca_public_key_openssh"), "Missing CA public key",
+					"Certificates can only be revoked by serial number for a specific CA.")
+				return diags
+			}
+			for j, s := range serials {
+				serial, err := parseSerial(s)
+				if err != nil {
+					diags.AddAttributeError(attributePath.AtName("serials").AtListIndex(j), "Failed to parse serial number", err.Error())
+					return diags
+				}
+				c.serials = append(c.serials, serial)
+			}
+			for j, serialRange := range serialRanges {
+				rangePath := attributePath.AtName("serial_ranges").AtListIndex(j)
+				minSerial, err := parseSerial(serialRange.Min.ValueString())
+				if err != nil {
+					diags.AddAttributeError(rangePath.AtName("min"), "Failed to parse serial number", err.Error())
+					return diags
+				}
+				maxSerial, err := parseSerial(serialRange.Max.ValueString())
+				if err != nil {
+					diags.AddAttributeError(rangePath.AtName("max"), "Failed to parse serial number", err.Error())
+					return diags
+				}
+				if minSerial > maxSerial {
+					diags.AddAttributeError(rangePath, "Invalid serial range",
+						fmt.Sprintf("min %d is greater than max %d", minSerial, maxSerial))
+					return diags
+				}
+				c.serialRanges = append(c.serialRanges, krlSerialRange{min: minSerial, max: maxSerial})
+			}
+		}
+	}
+
+	if !model.RevokedKeys.IsNull() {
+		var revokedKeys []string
+		diags.Append(model.RevokedKeys.ElementsAs(ctx, &revokedKeys, false)...)
+		if diags.HasError() {
+			return diags
+		}
+		for i, revokedKey := range revokedKeys {
+			pubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(revokedKey))
+			if err != nil {
+				diags.AddAttributeError(path.Root("revoked_keys").AtListIndex(i), "Failed to parse public key", err.Error())
+				return diags
+			}
+			k.revokeKey(pubKey)
+		}
+	}
+
+	if !model.RevokedFingerprintsSHA256.IsNull() {
+		var fingerprints []string
+		diags.Append(model.RevokedFingerprintsSHA256.ElementsAs(ctx, &fingerprints, false)...)
+		if diags.HasError() {
+			return diags
+		}
+		for i, fingerprint := range fingerprints {
+			hash, err := parseSHA256Fingerprint(fingerprint)
+			if err != nil {
+				diags.AddAttributeError(path.Root("revoked_fingerprints_sha256").AtListIndex(i), "Failed to parse fingerprint", err.Error())
+				return diags
+			}
+			k.fingerprintsSHA256 = append(k.fingerprintsSHA256, hash)
+		}
+	}
+
+	generatedTimeBytes, err := now.MarshalText()
+	if err != nil {
+		diags.AddError("Failed to serialize generated time", err.Error())
+		return diags
+	}
+
+	krlBytes := k.Marshal()
+	model.GeneratedTime = types.StringValue(string(generatedTimeBytes))
+	model.KRLBase64 = types.StringValue(base64.StdEncoding.EncodeToString(krlBytes))
+	model.ID = types.StringValue(fmt.Sprintf("%x", sha256.Sum256(krlBytes)))
+	return diags
+}
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestResourceKRL(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 setTimeForTest("2023-01-01T12:00:00Z"),
+		Steps: []r.TestStep{
+			{
+				Config: krlConfig(`"1", "100"`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("ssh_krl.test", "generated_time", "2023-01-01T12:00:00Z"),
+					r.TestCheckResourceAttrWith("ssh_krl.test", "krl_base64", func(value string) error {
+						b, err := base64.StdEncoding.DecodeString(value)
+						if err != nil {
+							return fmt.Errorf("error decoding KRL: %s", err)
+						}
+						if !bytes.HasPrefix(b, []byte("SSHKRL\n\x00")) {
+							return fmt.Errorf("incorrect KRL magic")
+						}
+						if expected, got := uint64(3), binary.BigEndian.Uint64(b[12:20]); got != expected {
+							return fmt.Errorf("incorrect KRL version: %v, wanted %v", got, expected)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config:      krlConfig(`"0"`),
+				ExpectError: regexp.MustCompile("must be a non-zero serial number in decimal"),
+			},
+		},
+	})
+}
+
+func krlConfig(serials string) string {
+	return providerConfig + fmt.Sprintf(`
+	resource "ssh_krl" "test" {
+		version = 3
+		comment = "test"
+		certificates = [
+			{
+				ca_public_key_openssh = %[1]q
+				serials               = [%[3]s]
+				serial_ranges = [
+					{
+						min = "1000"
+						max = "2000"
+					},
+				]
+				key_ids = ["testUser"]
+			},
+			{
+				key_ids = ["compromised"]
+			},
+		]
+		revoked_keys = [%[2]q]
+		revoked_fingerprints_sha256 = [
+			"SHA256:2nVc0iddaYtYlD6re4rFLou3dJZePXO7TJ35QLCSVOA",
+		]
+	}`, inputCAPublicKeyOpenSSH, inputPublicKeyOpenSSH, serials)
+}
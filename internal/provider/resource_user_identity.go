@@ -0,0 +1,543 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/randomcoww/terraform-provider-ssh/internal/provider/attribute_plan_modifier_bool"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &userIdentityResource{}
+var _ resource.ResourceWithModifyPlan = &userIdentityResource{}
+
+func NewUserIdentityResource() resource.Resource {
+	return &userIdentityResource{}
+}
+
+// userIdentityResource defines the resource implementation.
+type userIdentityResource struct {
+	revocationStore *revocationStore
+}
+
+// userIdentityResourceModel describes the resource data model.
+type userIdentityResourceModel struct {
+	CAPrivateKeyPEM      privateKeyPEMValue `tfsdk:"ca_private_key_pem"`
+	Algorithm            types.String       `tfsdk:"algorithm"`
+	RSABits              types.Int64        `tfsdk:"rsa_bits"`
+	ECDSACurve           types.String       `tfsdk:"ecdsa_curve"`
+	KeyID                types.String       `tfsdk:"key_id"`
+	ValidPrincipals      types.List         `tfsdk:"valid_principals"`
+	CriticalOptions      types.Map          `tfsdk:"critical_options"`
+	Extensions           types.Map          `tfsdk:"extensions"`
+	ValidityPeriodHours  types.Int64        `tfsdk:"validity_period_hours"`
+	EarlyRenewalHours    types.Int64        `tfsdk:"early_renewal_hours"`
+	EarlyRenewalFraction types.Float64      `tfsdk:"early_renewal_fraction"`
+	EarlyRenewal         types.String       `tfsdk:"early_renewal"`
+	EarlyRenewalJitter   types.String       `tfsdk:"early_renewal_jitter"`
+	AlignExpiryTo        types.String       `tfsdk:"align_expiry_to"`
+	Comment              types.String       `tfsdk:"comment"`
+	RevokeOnDestroy      types.Bool         `tfsdk:"revoke_on_destroy"`
+	SSHConfigHost        types.String       `tfsdk:"ssh_config_host"`
+	SSHConfigUser        types.String       `tfsdk:"ssh_config_user"`
+	IdentityFile         types.String       `tfsdk:"identity_file"`
+	PrivateKeyOpenSSH    types.String       `tfsdk:"private_key_openssh"`
+	PublicKeyOpenSSH     types.String       `tfsdk:"public_key_openssh"`
+	PublicKeyFingerprint types.String       `tfsdk:"public_key_fingerprint_sha256"`
+	CertAuthorizedKey    types.String       `tfsdk:"cert_authorized_key"`
+	ReadyForRenewal      types.Bool         `tfsdk:"ready_for_renewal"`
+	ValidityStartTime    types.String       `tfsdk:"validity_start_time"`
+	ValidityEndTime      types.String       `tfsdk:"validity_end_time"`
+	CAPublicKeyOpenSSH   types.String       `tfsdk:"ca_public_key_openssh"`
+	CAKeyFingerprint     types.String       `tfsdk:"ca_key_fingerprint_sha256"`
+	SSHConfig            types.String       `tfsdk:"ssh_config"`
+	ID                   types.String       `tfsdk:"id"`
+}
+
+func (r *userIdentityResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_identity"
+}
+
+func (r *userIdentityResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Create an SSH key pair, sign it as a user certificate, and generate the matching " +
+			"`~/.ssh/config` entry. The key pair and certificate are renewed together in place when the certificate is ready for renewal",
+
+		Attributes: map[string]schema.Attribute{
+			"ca_private_key_pem": schema.StringAttribute{
+				CustomType: privateKeyPEMType{},
+				Required:   true,
+				PlanModifiers: []planmodifier.String{
+					requireReplaceIfPrivateKeyChanged(),
+				},
+				Sensitive: true,
+				Description: "Private key of the Certificate Authority (CA) used to sign the certificate, " +
+					"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"Only a different key reissues the certificate, not a different encoding of the same key.",
+			},
+			"algorithm": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(ED25519.String()),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(RSA.String(), ECDSA.String(), ED25519.String()),
+				},
+				Description: "Algorithm of the generated key pair: `RSA`, `ECDSA` or `ED25519`. (default: `ED25519`)",
+			},
+			"rsa_bits": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(3072),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(2048),
+				},
+				Description: "Size of the generated RSA key, in bits. (default: `3072`)",
+			},
+			"ecdsa_curve": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(P256.String()),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(P256.String(), P384.String(), P521.String()),
+				},
+				Description: "Curve of the generated ECDSA key: `P256`, `P384` or `P521`. (default: `P256`)",
+			},
+			"key_id": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Description: "User identifier for certificate.",
+			},
+			"valid_principals": schema.ListAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(principalValidator()),
+				},
+				Description: "List of user names to use as subjects of the certificate.",
+			},
+			"critical_options": schema.MapAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+				Description: "Map of critical options for certificate usage permissions.",
+			},
+			"extensions": schema.MapAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+				Description: "Map of extensions for certificate usage permissions.",
+			},
+			"validity_period_hours": schema.Int64Attribute{
+				Required: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				Description: "Number of hours, after initial issuing, that the certificate will remain valid for.",
+			},
+
+			// Optional
+			"early_renewal_hours": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(0),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				Description: "The resource will consider the certificate to have expired the given number of hours " +
+					"before its actual expiry time, and renew both the key pair and the certificate in place when the " +
+					"Terraform configuration is next applied. (default: `0`)",
+			},
+			"early_renewal_fraction": earlyRenewalFractionAttribute(),
+			"early_renewal":          earlyRenewalAttribute(),
+			"early_renewal_jitter":   earlyRenewalJitterAttribute(),
+			"align_expiry_to":        alignExpiryToAttribute(stringplanmodifier.RequiresReplace()),
+			"comment": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(""),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Description: "Comment of the generated key pair, also appended to `public_key_openssh` and `cert_authorized_key`. " +
+					"(default: `\"\"`)",
+			},
+			"revoke_on_destroy": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "Record the certificate serial number, key ID and CA in the provider `revocation_store_path` " +
+					"when the resource is destroyed or replaced, or the certificate is renewed, as with `ssh_user_cert`. (default: `false`)",
+			},
+			"ssh_config_host": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("*"),
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(knownHostsPatternRegexp, "must not contain whitespace or commas"),
+				},
+				Description: "Host pattern of the `Host` entry in `ssh_config`. (default: `*`)",
+			},
+			"ssh_config_user": schema.StringAttribute{
+				Optional:    true,
+				Description: "Value of the `User` option in `ssh_config`, if any.",
+			},
+			"identity_file": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				Description: "Path the private key is installed at, for the `IdentityFile` option in `ssh_config`. " +
+					"The certificate is expected at the same path with a `-cert.pub` suffix, for the `CertificateFile` option. " +
+					"(default: `~/.ssh/id_<algorithm>`, with the algorithm in lower case, as created by `ssh-keygen`)",
+			},
+			"private_key_openssh": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Generated private key, in OpenSSH format.",
+			},
+			"public_key_openssh": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Generated public key, in authorized keys format.",
+			},
+			"public_key_fingerprint_sha256": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "SHA256 fingerprint of the generated public key, " +
+					"in the format printed by `ssh-keygen -l`.",
+			},
+			"cert_authorized_key": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Signed SSH certificate, in authorized keys format.",
+			},
+			"ready_for_renewal": schema.BoolAttribute{
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					attribute_plan_modifier_bool.ReadyForRenewal(),
+				},
+				Description: "Is the certificate either expired (i.e. beyond the `validity_period_hours`) " +
+					"or ready for an early renewal (i.e. within `early_renewal_hours`, `early_renewal_fraction` or `early_renewal`)?",
+			},
+			"validity_start_time": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "The time after which the certificate is valid, " +
+					"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+			},
+			"validity_end_time": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "The time until which the certificate is invalid, " +
+					"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+			},
+			"ca_public_key_openssh": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Public key of the Certificate Authority (CA), in authorized keys format. " +
+					"Suitable for use in `TrustedUserCAKeys`.",
+			},
+			"ca_key_fingerprint_sha256": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "SHA256 fingerprint of the Certificate Authority (CA) public key, " +
+					"in the format printed by `ssh-keygen -l`.",
+			},
+			"ssh_config": schema.StringAttribute{
+				Computed:    true,
+				Description: "`Host` entry of `~/.ssh/config` using the installed key pair and certificate.",
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Unique identifier for this resource: the certificate serial number.",
+			},
+		},
+	}
+}
+
+func (r *userIdentityResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	store, ok := req.ProviderData.(*revocationStore)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *revocationStore, got: %T. Please report this issue to the provider developers.", req.ProviderData))
+		return
+	}
+	r.revocationStore = store
+}
+
+func (r *userIdentityResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var newState userIdentityResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(issueUserIdentity(ctx, &req.Plan, &newState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	newState.SSHConfig = types.StringValue(userIdentitySSHConfig(&newState))
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+// issueUserIdentity generates a new key pair and signs it, as planned in the model.
+func issueUserIdentity(ctx context.Context, plan *tfsdk.Plan, model *userIdentityResourceModel) diag.Diagnostics {
+	certificate, diags := baseCertificate(ctx, plan)
+	if diags.HasError() {
+		return diags
+	}
+	certificate.CertType = ssh.UserCert
+
+	signer, d := parseCASigner(model.CAPrivateKeyPEM.ValueString())
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	prvKey, err := generatePrivateKey(Algorithm(model.Algorithm.ValueString()), int(model.RSABits.ValueInt64()), ECDSACurve(model.ECDSACurve.ValueString()))
+	if err != nil {
+		diags.AddAttributeError(path.Root("algorithm"), "Failed to generate key pair", err.Error())
+		return diags
+	}
+	privateKeyOpenSSH, err := marshalPrivateKeyOpenSSH(prvKey, model.Comment.ValueString())
+	if err != nil {
+		diags.AddError("Failed to serialize private key", err.Error())
+		return diags
+	}
+	pubKey, err := ssh.NewPublicKey(prvKey.Public())
+	if err != nil {
+		diags.AddError("Failed to create public key", err.Error())
+		return diags
+	}
+	certificate.Key = pubKey
+
+	if err := certificate.SignCert(rand.Reader, signer); err != nil {
+		diags.AddError("Failed sign cert", err.Error())
+		return diags
+	}
+
+	validFromBytes, err := time.Unix(int64(certificate.ValidAfter), 0).MarshalText()
+	if err != nil {
+		diags.AddError("Failed to serialize validity start time", err.Error())
+		return diags
+	}
+	validToBytes, err := time.Unix(int64(certificate.ValidBefore), 0).MarshalText()
+	if err != nil {
+		diags.AddError("Failed to serialize validity end time", err.Error())
+		return diags
+	}
+
+	comment := model.Comment.ValueString()
+	model.ID = types.StringValue(fmt.Sprintf("%d", certificate.Serial))
+	model.PrivateKeyOpenSSH = types.StringValue(privateKeyOpenSSH)
+	model.PublicKeyOpenSSH = types.StringValue(authorizedKeysLine(authorizedKeysOptions{}, pubKey, comment) + "\n")
+	model.PublicKeyFingerprint = types.StringValue(ssh.FingerprintSHA256(pubKey))
+	model.CertAuthorizedKey = types.StringValue(marshalCertificate(certificate, comment))
+	model.ValidityStartTime = types.StringValue(string(validFromBytes))
+	model.ValidityEndTime = types.StringValue(string(validToBytes))
+	model.CAPublicKeyOpenSSH = types.StringValue(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+	model.CAKeyFingerprint = types.StringValue(ssh.FingerprintSHA256(signer.PublicKey()))
+	if model.ReadyForRenewal.IsUnknown() {
+		model.ReadyForRenewal = types.BoolValue(false)
+	}
+	return diags
+}
+
+func (r *userIdentityResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	modifyStateIfCertificateReadyForRenewal(ctx, req, resp, path.Root("id"))
+}
+
+// Update renews the key pair and certificate if planned, and otherwise stores the plan. A renewed
+// certificate is recorded in the revocation store if revoke_on_destroy is set, as when it is destroyed.
+func (r *userIdentityResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var newState, state userIdentityResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &newState)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if newState.ID.IsUnknown() {
+		resp.Diagnostics.Append(issueUserIdentity(ctx, &req.Plan, &newState)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if state.RevokeOnDestroy.ValueBool() && !newState.ID.Equal(state.ID) {
+		resp.Diagnostics.Append(recordRevokedCertificate(r.revocationStore, revocationRecord{
+			Serial:             state.ID.ValueString(),
+			KeyID:              state.KeyID.ValueString(),
+			CAPublicKeyOpenSSH: state.CAPublicKeyOpenSSH.ValueString(),
+			CAKeyFingerprint:   state.CAKeyFingerprint.ValueString(),
+		})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	newState.SSHConfig = types.StringValue(userIdentitySSHConfig(&newState))
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+// Delete records the certificate in the revocation store if revoke_on_destroy is set.
+func (r *userIdentityResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state userIdentityResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !state.RevokeOnDestroy.ValueBool() {
+		return
+	}
+
+	resp.Diagnostics.Append(recordRevokedCertificate(r.revocationStore, revocationRecord{
+		Serial:             state.ID.ValueString(),
+		KeyID:              state.KeyID.ValueString(),
+		CAPublicKeyOpenSSH: state.CAPublicKeyOpenSSH.ValueString(),
+		CAKeyFingerprint:   state.CAKeyFingerprint.ValueString(),
+	})...)
+}
+
+func (r *userIdentityResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Only revoke_on_destroy is checked if the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		resp.Diagnostics.Append(validateRevokeOnDestroy(ctx, req.State, req.Plan, r.revocationStore)...)
+		return
+	}
+	resp.Diagnostics.Append(validateCertificateSchedule(ctx, req.Plan)...)
+	modifyPlanForUserIdentityRenewal(ctx, &req, resp)
+
+	var plan userIdentityResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	sshConfig := types.StringUnknown()
+	if !plan.Algorithm.IsUnknown() && !plan.SSHConfigHost.IsUnknown() && !plan.SSHConfigUser.IsUnknown() && !plan.IdentityFile.IsUnknown() {
+		sshConfig = types.StringValue(userIdentitySSHConfig(&plan))
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ssh_config"), sshConfig)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateRevokeOnDestroy(ctx, req.State, resp.Plan, r.revocationStore)...)
+}
+
+// modifyPlanForUserIdentityRenewal plans to generate a new key pair and certificate in place once the
+// certificate is ready for renewal, as ssh_user_cert and ssh_host_cert re-issue their certificates.
+func modifyPlanForUserIdentityRenewal(ctx context.Context, req *resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() {
+		return
+	}
+	earlyRenewalTime, known, diags := getCertificateRenewalTime(ctx, req.Plan, path.Root("id"))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || !known || overridableTimeFunc().Before(earlyRenewalTime) {
+		return
+	}
+	tflog.Info(ctx, "Certificate is ready for early renewal, renewing the key pair and certificate")
+
+	var plan userIdentityResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.ID = types.StringUnknown()
+	plan.PrivateKeyOpenSSH = types.StringUnknown()
+	plan.PublicKeyOpenSSH = types.StringUnknown()
+	plan.PublicKeyFingerprint = types.StringUnknown()
+	plan.CertAuthorizedKey = types.StringUnknown()
+	plan.ValidityStartTime = types.StringUnknown()
+	plan.ValidityEndTime = types.StringUnknown()
+	plan.ReadyForRenewal = types.BoolUnknown()
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+// userIdentitySSHConfig returns the `Host` entry of ssh_config for the key pair and certificate.
+func userIdentitySSHConfig(model *userIdentityResourceModel) string {
+	identityFile := "~/.ssh/id_" + strings.ToLower(model.Algorithm.ValueString())
+	if !model.IdentityFile.IsNull() {
+		identityFile = model.IdentityFile.ValueString()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Host %s\n", model.SSHConfigHost.ValueString())
+	if !model.SSHConfigUser.IsNull() {
+		fmt.Fprintf(&b, "  User %s\n", model.SSHConfigUser.ValueString())
+	}
+	fmt.Fprintf(&b, "  IdentityFile %s\n", identityFile)
+	fmt.Fprintf(&b, "  CertificateFile %s-cert.pub\n", identityFile)
+	return b.String()
+}
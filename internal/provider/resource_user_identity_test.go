@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestResourceUserIdentity(t *testing.T) {
+	var publicKey string
+	checkRotated := func(changed bool) r.TestCheckFunc {
+		return r.TestCheckResourceAttrWith("ssh_user_identity.test", "public_key_openssh", func(value string) error {
+			if (value != publicKey) != changed {
+				return fmt.Errorf("key pair rotated: %t, wanted %t", value != publicKey, changed)
+			}
+			publicKey = value
+			return nil
+		})
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 setTimeForTest("2023-01-01T12:00:00Z"),
+		Steps: []r.TestStep{
+			{
+				Config: userIdentityConfig(""),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("ssh_user_identity.test", "algorithm", "ED25519"),
+					r.TestCheckResourceAttr("ssh_user_identity.test", "ca_public_key_openssh", inputCAPublicKeyOpenSSH+"\n"),
+					r.TestCheckResourceAttr("ssh_user_identity.test", "validity_end_time", "2023-01-01T22:00:00Z"),
+					r.TestCheckResourceAttr("ssh_user_identity.test", "ssh_config", `Host *.example.com
+  User deploy
+  IdentityFile ~/.ssh/id_ed25519
+  CertificateFile ~/.ssh/id_ed25519-cert.pub
+`),
+					func(s *terraform.State) error {
+						attributes := s.RootModule().Resources["ssh_user_identity.test"].Primary.Attributes
+						prvKey, err := ssh.ParseRawPrivateKey([]byte(attributes["private_key_openssh"]))
+						if err != nil {
+							return err
+						}
+						signer, err := ssh.NewSignerFromKey(prvKey)
+						if err != nil {
+							return err
+						}
+						certificate, err := parseCertificate(attributes["cert_authorized_key"])
+						if err != nil {
+							return err
+						}
+						if !bytes.Equal(certificate.Key.Marshal(), signer.PublicKey().Marshal()) {
+							return fmt.Errorf("certificate does not match the private key")
+						}
+						if certificate.CertType != ssh.UserCert || certificate.KeyId != "ci-runner" ||
+							fmt.Sprintf("%d", certificate.Serial) != attributes["id"] {
+							return fmt.Errorf("incorrect certificate:\n%s", certificateText(certificate))
+						}
+						if _, ok := certificate.Extensions["permit-pty"]; !ok {
+							return fmt.Errorf("missing extension:\n%s", certificateText(certificate))
+						}
+						return nil
+					},
+					checkRotated(true),
+				),
+			},
+			{
+				PreConfig: setTimeForTest("2023-01-01T19:00:00Z"),
+				Config:    userIdentityConfig(`identity_file = "/home/deploy/.ssh/ci"`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("ssh_user_identity.test", "ssh_config", `Host *.example.com
+  User deploy
+  IdentityFile /home/deploy/.ssh/ci
+  CertificateFile /home/deploy/.ssh/ci-cert.pub
+`),
+					checkRotated(false),
+				),
+			},
+			{
+				PreConfig: setTimeForTest("2023-01-01T21:00:00Z"),
+				Config:    userIdentityConfig(`identity_file = "/home/deploy/.ssh/ci"`),
+				ConfigPlanChecks: r.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("ssh_user_identity.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("ssh_user_identity.test", "validity_end_time", "2023-01-02T07:00:00Z"),
+					r.TestCheckResourceAttr("ssh_user_identity.test", "ready_for_renewal", "false"),
+					checkRotated(true),
+				),
+			},
+		},
+	})
+}
+
+func userIdentityConfig(extra string) string {
+	return providerConfig + fmt.Sprintf(`
+	resource "ssh_user_identity" "test" {
+		ca_private_key_pem = <<EOT
+%s
+EOT
+		key_id                = "ci-runner"
+		valid_principals      = ["deploy"]
+		critical_options      = {}
+		extensions            = {
+			"permit-pty" = ""
+		}
+		validity_period_hours = 10
+		early_renewal_hours   = 2
+		ssh_config_host       = "*.example.com"
+		ssh_config_user       = "deploy"
+		%s
+	}`, inputPrivateKey, extra)
+}
@@ -69,6 +69,8 @@ func pemBlockToPEMPreamble(block *pem.Block) (PEMPreamble, error) {
 		return PreamblePrivateKeyRSA, nil
 	case PreamblePrivateKeyEC.String():
 		return PreamblePrivateKeyEC, nil
+	case PreamblePrivateKeyOpenSSH.String():
+		return PreamblePrivateKeyOpenSSH, nil
 	case PreambleCertificate.String():
 		return PreambleCertificate, nil
 	case PreambleCertificateRequest.String():
@@ -0,0 +1,266 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Ensure the custom types fully satisfy framework interfaces.
+var _ basetypes.StringTypable = privateKeyPEMType{}
+var _ basetypes.StringValuableWithSemanticEquals = privateKeyPEMValue{}
+var _ basetypes.StringTypable = publicKeyOpenSSHType{}
+var _ basetypes.StringValuableWithSemanticEquals = publicKeyOpenSSHValue{}
+
+// privateKeyPEMType is the type of private keys in PEM or OpenSSH format.
+// Values are semantically equal if they hold the same private key, regardless of its encoding.
+type privateKeyPEMType struct {
+	basetypes.StringType
+}
+
+func (t privateKeyPEMType) Equal(o attr.Type) bool {
+	other, ok := o.(privateKeyPEMType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t privateKeyPEMType) String() string {
+	return "privateKeyPEMType"
+}
+
+func (t privateKeyPEMType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return privateKeyPEMValue{StringValue: in}, nil
+}
+
+func (t privateKeyPEMType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+	stringValuable, diags := t.ValueFromString(ctx, stringValue)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to StringValuable: %v", diags)
+	}
+	return stringValuable, nil
+}
+
+func (t privateKeyPEMType) ValueType(ctx context.Context) attr.Value {
+	return privateKeyPEMValue{}
+}
+
+// privateKeyPEMValue is a value of privateKeyPEMType.
+type privateKeyPEMValue struct {
+	basetypes.StringValue
+}
+
+func newPrivateKeyPEMNull() privateKeyPEMValue {
+	return privateKeyPEMValue{StringValue: basetypes.NewStringNull()}
+}
+
+func (v privateKeyPEMValue) Equal(o attr.Value) bool {
+	other, ok := o.(privateKeyPEMValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v privateKeyPEMValue) Type(ctx context.Context) attr.Type {
+	return privateKeyPEMType{}
+}
+
+// StringSemanticEquals compares the private keys. Values that cannot be parsed are only
+// equal if they are the same string.
+func (v privateKeyPEMValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(privateKeyPEMValue)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got value type %T. Please report this issue to the provider developers.", v, newValuable))
+		return false, diags
+	}
+
+	prvKey, _, err := parsePrivateKeyPEM([]byte(v.ValueString()))
+	if err != nil {
+		return false, diags
+	}
+	newPrvKey, _, err := parsePrivateKeyPEM([]byte(newValue.ValueString()))
+	if err != nil {
+		return false, diags
+	}
+	comparable, ok := prvKey.(interface{ Equal(crypto.PrivateKey) bool })
+	if !ok {
+		return false, diags
+	}
+	return comparable.Equal(newPrvKey), diags
+}
+
+// publicKeyOpenSSHType is the type of SSH public keys in authorized keys or RFC 4716 format.
+// Values are semantically equal if they hold the same public key, regardless of its format and comment.
+type publicKeyOpenSSHType struct {
+	basetypes.StringType
+}
+
+func (t publicKeyOpenSSHType) Equal(o attr.Type) bool {
+	other, ok := o.(publicKeyOpenSSHType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t publicKeyOpenSSHType) String() string {
+	return "publicKeyOpenSSHType"
+}
+
+func (t publicKeyOpenSSHType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return publicKeyOpenSSHValue{StringValue: in}, nil
+}
+
+func (t publicKeyOpenSSHType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+	stringValuable, diags := t.ValueFromString(ctx, stringValue)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to StringValuable: %v", diags)
+	}
+	return stringValuable, nil
+}
+
+func (t publicKeyOpenSSHType) ValueType(ctx context.Context) attr.Value {
+	return publicKeyOpenSSHValue{}
+}
+
+// publicKeyOpenSSHValue is a value of publicKeyOpenSSHType.
+type publicKeyOpenSSHValue struct {
+	basetypes.StringValue
+}
+
+func newPublicKeyOpenSSHValue(value string) publicKeyOpenSSHValue {
+	return publicKeyOpenSSHValue{StringValue: basetypes.NewStringValue(value)}
+}
+
+func (v publicKeyOpenSSHValue) Equal(o attr.Value) bool {
+	other, ok := o.(publicKeyOpenSSHValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v publicKeyOpenSSHValue) Type(ctx context.Context) attr.Type {
+	return publicKeyOpenSSHType{}
+}
+
+// StringSemanticEquals compares the public keys, ignoring comments. Values that cannot be
+// parsed are only equal if they are the same string.
+func (v publicKeyOpenSSHValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(publicKeyOpenSSHValue)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got value type %T. Please report this issue to the provider developers.", v, newValuable))
+		return false, diags
+	}
+
+	pubKey, _, err := parsePublicKeyOpenSSH(v.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	newPubKey, _, err := parsePublicKeyOpenSSH(newValue.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	return bytes.Equal(pubKey.Marshal(), newPubKey.Marshal()), diags
+}
+
+// requireReplaceIfPrivateKeyChanged returns a planmodifier.String that triggers a replacement
+// of the resource if the private key in the state is replaced by a different key.
+// Re-encoding the same key does not trigger a replacement, nor does setting a key that is not in
+// the state, e.g. after an import.
+func requireReplaceIfPrivateKeyChanged() planmodifier.String {
+	description := "Attribute requires replacement if it contains a different private key"
+
+	return stringplanmodifier.RequiresReplaceIf(func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+		if req.StateValue.IsNull() {
+			return
+		}
+		equal, diags := privateKeyPEMValue{StringValue: req.StateValue}.StringSemanticEquals(ctx, privateKeyPEMValue{StringValue: req.PlanValue})
+		resp.Diagnostics.Append(diags...)
+		resp.RequiresReplace = !equal
+	}, description, description)
+}
+
+// requireReplaceIfPublicKeyChanged returns a planmodifier.String that triggers a replacement
+// of the resource if the public key is replaced by a different key. Changing only the format
+// or comment of the key does not trigger a replacement.
+func requireReplaceIfPublicKeyChanged() planmodifier.String {
+	description := "Attribute requires replacement if it contains a different public key"
+
+	return stringplanmodifier.RequiresReplaceIf(func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+		equal, diags := publicKeyOpenSSHValue{StringValue: req.StateValue}.StringSemanticEquals(ctx, publicKeyOpenSSHValue{StringValue: req.PlanValue})
+		resp.Diagnostics.Append(diags...)
+		resp.RequiresReplace = !equal
+	}, description, description)
+}
+
+// requireReplaceIfPublicKeysChanged returns a planmodifier.List that triggers a replacement
+// of the resource if the list of public keys changes other than in the format or comments of the keys.
+func requireReplaceIfPublicKeysChanged() planmodifier.List {
+	description := "Attribute requires replacement if it contains different public keys"
+
+	return listplanmodifier.RequiresReplaceIf(func(ctx context.Context, req planmodifier.ListRequest, resp *listplanmodifier.RequiresReplaceIfFuncResponse) {
+		if req.StateValue.IsNull() || req.PlanValue.IsNull() || len(req.StateValue.Elements()) != len(req.PlanValue.Elements()) {
+			resp.RequiresReplace = true
+			return
+		}
+		planElements := req.PlanValue.Elements()
+		for i, stateElement := range req.StateValue.Elements() {
+			stateValue, ok := stateElement.(publicKeyOpenSSHValue)
+			if !ok {
+				resp.RequiresReplace = true
+				return
+			}
+			planValue, ok := planElements[i].(publicKeyOpenSSHValue)
+			if !ok || planValue.IsUnknown() {
+				resp.RequiresReplace = true
+				return
+			}
+			equal, diags := stateValue.StringSemanticEquals(ctx, planValue)
+			resp.Diagnostics.Append(diags...)
+			if !equal {
+				resp.RequiresReplace = true
+				return
+			}
+		}
+	}, description, description)
+}
+
+// publicKeysOpenSSHNull returns a null list of public keys of publicKeyOpenSSHType.
+func publicKeysOpenSSHNull() types.List {
+	return types.ListNull(publicKeyOpenSSHType{})
+}